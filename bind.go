@@ -0,0 +1,224 @@
+package gdo
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var ErrNotStruct = errors.New("gdo: can only bind a struct or a pointer to one")
+var ErrUnusedField = errors.New("gdo: value is not used by the statement")
+
+// BindOption changes how BindStruct and BindMap bind values
+type BindOption func(*bindOptions)
+
+type bindOptions struct {
+	errorOnUnused bool
+}
+
+// ErrorOnUnused makes BindStruct and BindMap fail with ErrUnusedField when a
+// field or key does not match any parameter of the statement
+func ErrorOnUnused() BindOption {
+	return func(o *bindOptions) {
+		o.errorOnUnused = true
+	}
+}
+
+type bindValue struct {
+	name  string
+	value interface{}
+}
+
+// binding is a call to BindStruct or BindMap, which added n named args to the
+// statement from namedArgs[at] on
+type binding struct {
+	values []bindValue
+	opts   bindOptions
+	at, n  int
+}
+
+// BindStruct binds the exported fields of v, a struct or a pointer to one, to
+// the named parameters of the statement, which may also be a
+// PreparedStatement.  A field is bound under its gdo tag
+// or else its name, matched case-insensitively, so IntCol binds :intCol:.
+// Fields of nested structs are prefixed with the name of the struct field and
+// a dot, e.g. :address.city:, while embedded structs add no prefix.  Fields
+// tagged gdo:"-" are skipped.  The fields are matched again when the
+// statement is executed in a dialect that reads its query differently.
+func (stmt *Statement) BindStruct(v interface{}, opts ...BindOption) error {
+	values, err := structValues(v)
+
+	if err != nil {
+		return err
+	}
+
+	return stmt.bindValues(stmt.template().names, values, opts)
+}
+
+// BindMap binds the values of m to the named parameters matching its keys
+func (stmt *Statement) BindMap(m map[string]interface{}, opts ...BindOption) error {
+	return stmt.bindValues(stmt.template().names, mapValues(m), opts)
+}
+
+func (stmt *Statement) bindValues(names []string, values []bindValue, opts []BindOption) error {
+	var o bindOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	namedArgs, err := matchValues(names, values, o)
+
+	if err != nil {
+		return err
+	}
+
+	stmt.binds = append(stmt.binds, binding{values: values, opts: o, at: len(stmt.namedArgs), n: len(namedArgs)})
+	stmt.namedArgs = append(stmt.namedArgs, namedArgs...)
+
+	return nil
+}
+
+// matchValues returns a named arg for each of names that one of values
+// matches, by name or else case-insensitively
+func matchValues(names []string, values []bindValue, o bindOptions) ([]sql.NamedArg, error) {
+	exact := make(map[string]int, len(values))
+	folded := make(map[string]int, len(values))
+
+	for i, v := range values {
+		exact[v.name] = i
+
+		if _, ok := folded[strings.ToLower(v.name)]; !ok {
+			folded[strings.ToLower(v.name)] = i
+		}
+	}
+
+	used := make([]bool, len(values))
+
+	var namedArgs []sql.NamedArg
+
+	for _, name := range names {
+		i, ok := exact[name]
+
+		if !ok {
+			i, ok = folded[strings.ToLower(name)]
+		}
+
+		if !ok {
+			continue
+		}
+
+		used[i] = true
+
+		namedArgs = append(namedArgs, sql.Named(name, values[i].value))
+	}
+
+	if o.errorOnUnused {
+		for i, u := range used {
+			if !u {
+				return nil, fmt.Errorf("%w: %s", ErrUnusedField, values[i].name)
+			}
+		}
+	}
+
+	return namedArgs, nil
+}
+
+func mapValues(m map[string]interface{}) []bindValue {
+	values := make([]bindValue, 0, len(m))
+
+	for k, v := range m {
+		values = append(values, bindValue{name: k, value: v})
+	}
+
+	return values
+}
+
+func structValues(v interface{}) ([]bindValue, error) {
+	rv := reflect.ValueOf(v)
+
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+
+	return appendStructValues(nil, rv, ""), nil
+}
+
+func appendStructValues(values []bindValue, rv reflect.Value, prefix string) []bindValue {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		// unexported embedded structs still promote their exported fields
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("gdo")
+
+		if tag == "-" {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = field.Name
+		}
+
+		fv := rv.Field(i)
+
+		if isBindLeaf(field.Type) {
+			if field.PkgPath == "" {
+				values = append(values, bindValue{name: prefix + name, value: fv.Interface()})
+			}
+
+			continue
+		}
+
+		for fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				break
+			}
+
+			fv = fv.Elem()
+		}
+
+		if fv.Kind() != reflect.Struct {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			values = appendStructValues(values, fv, prefix)
+		} else {
+			values = appendStructValues(values, fv, prefix+name+".")
+		}
+	}
+
+	return values
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+var timeType = reflect.TypeOf(time.Time{})
+
+// isBindLeaf reports whether a field of type t is bound as a single value
+// rather than descended into
+func isBindLeaf(t reflect.Type) bool {
+	if t.Implements(valuerType) || reflect.PtrTo(t).Implements(valuerType) {
+		return true
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() != reflect.Struct || t == timeType
+}
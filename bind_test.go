@@ -0,0 +1,103 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type bindAddress struct {
+	City string
+	Zip  string `gdo:"postal_code"`
+}
+
+type bindAudit struct {
+	CreatedAt time.Time
+}
+
+type bindUser struct {
+	bindAudit
+	ID       int `gdo:"id"`
+	Name     string
+	Nickname sql.NullString
+	Address  bindAddress `gdo:"addr"`
+	Previous *bindAddress
+	Password string `gdo:"-"`
+	internal int
+}
+
+func TestBindStruct(t *testing.T) {
+	now := time.Now()
+
+	u := bindUser{
+		bindAudit: bindAudit{CreatedAt: now},
+		ID:        1,
+		Name:      "foo",
+		Nickname:  sql.NullString{String: "f", Valid: true},
+		Address:   bindAddress{City: "bar", Zip: "12345"},
+		Password:  "secret",
+	}
+
+	stmt := NewStatement("INSERT INTO Users VALUES (:id:, :name:, :Nickname:, :addr.City:, :addr.postal_code:, :createdAt:)")
+
+	assert.NoError(t, stmt.BindStruct(&u))
+
+	newStmt, err := processStatment(newConfig(nil), stmt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, "foo", u.Nickname, "bar", "12345", now}, newStmt.args)
+}
+
+func TestBindStructErrors(t *testing.T) {
+	stmt := NewStatement("SELECT * FROM Users WHERE id = :id:")
+
+	assert.Equal(t, ErrNotStruct, stmt.BindStruct(1))
+	assert.Equal(t, ErrNotStruct, stmt.BindStruct(map[string]interface{}{}))
+
+	err := stmt.BindStruct(bindAddress{}, ErrorOnUnused())
+
+	assert.True(t, errors.Is(err, ErrUnusedField))
+	assert.Empty(t, stmt.namedArgs)
+
+	assert.NoError(t, stmt.BindStruct(struct{ ID int }{ID: 2}, ErrorOnUnused()))
+	assert.Equal(t, []sql.NamedArg{sql.Named("id", 2)}, stmt.namedArgs)
+}
+
+func TestBindMap(t *testing.T) {
+	stmt := NewStatement("SELECT * FROM Users WHERE id = :id: AND name = :name:")
+
+	err := stmt.BindMap(map[string]interface{}{"id": 1, "name": "foo", "other": 2}, ErrorOnUnused())
+
+	assert.True(t, errors.Is(err, ErrUnusedField))
+
+	assert.NoError(t, stmt.BindMap(map[string]interface{}{"id": 1, "name": "foo", "other": 2}))
+
+	newStmt, err := processStatment(newConfig(nil), stmt)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, "foo"}, newStmt.args)
+}
+
+func TestPreparedStatementBindStruct(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta("UPDATE Users SET name = ? WHERE id = ?")).
+		ExpectExec().WithArgs("foo", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ps, err := New(db).PrepareContext(context.Background(), "UPDATE Users SET name = :name: WHERE id = :id:")
+	assert.NoError(t, err)
+
+	assert.NoError(t, ps.BindStruct(bindUser{ID: 1, Name: "foo"}))
+
+	_, err = ps.Exec()
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
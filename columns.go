@@ -0,0 +1,236 @@
+package gdo
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ColumnType describes a column of a result as reported by the driver.  The
+// Known fields are false when the driver does not report the value next to
+// them.
+type ColumnType struct {
+	Name string
+	// DatabaseType is the upper case name of the type in the database,
+	// e.g. "VARCHAR", "DECIMAL" or "TIMESTAMPTZ"
+	DatabaseType string
+
+	Nullable      bool
+	NullableKnown bool
+
+	Precision        int64
+	Scale            int64
+	DecimalSizeKnown bool
+
+	Length      int64
+	LengthKnown bool
+
+	// ScanType is the Go type the driver would scan the column into
+	ScanType reflect.Type
+}
+
+func newColumnType(ct *sql.ColumnType) ColumnType {
+	c := ColumnType{
+		Name:         ct.Name(),
+		DatabaseType: strings.ToUpper(ct.DatabaseTypeName()),
+		ScanType:     ct.ScanType(),
+	}
+
+	c.Nullable, c.NullableKnown = ct.Nullable()
+	c.Precision, c.Scale, c.DecimalSizeKnown = ct.DecimalSize()
+	c.Length, c.LengthKnown = ct.Length()
+
+	return c
+}
+
+// ColumnTypes returns the type of each column of the result, in order
+func (r QueryResult) ColumnTypes() ([]ColumnType, error) {
+	if r.cursor != nil && r.cursor.types != nil {
+		return r.cursor.types, nil
+	}
+
+	if r.Rows == nil {
+		return nil, ErrNoCursor
+	}
+
+	cts, err := r.Rows.ColumnTypes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	types := make([]ColumnType, len(cts))
+
+	for i, ct := range cts {
+		types[i] = newColumnType(ct)
+	}
+
+	if r.cursor != nil {
+		r.cursor.types = types
+	}
+
+	return types, nil
+}
+
+// columnTypesByName returns the types of the columns keyed by name, built once
+// per result
+func (r QueryResult) columnTypesByName() (map[string]ColumnType, error) {
+	if r.cursor != nil && r.cursor.byName != nil {
+		return r.cursor.byName, nil
+	}
+
+	types, err := r.ColumnTypes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]ColumnType, len(types))
+
+	for _, t := range types {
+		byName[t.Name] = t
+	}
+
+	if r.cursor != nil {
+		r.cursor.byName = byName
+	}
+
+	return byName, nil
+}
+
+// MetaRow is a Row along with the types of its columns and the time zone of
+// the GDO it was read with.  Numbers are read as Row reads them, which parses
+// numeric text whatever the declared type of its column.
+type MetaRow struct {
+	Row
+	// Columns holds the type of each column by name.  It is shared by all
+	// the rows of a result.
+	Columns map[string]ColumnType
+	// Location is the time zone set with WithLocation, which Time and Date
+	// read Unix times and times without a zone in
+	Location *time.Location
+}
+
+// MetaRow returns the row Next advanced to along with its column types
+func (r QueryResult) MetaRow() (MetaRow, error) {
+	cols, err := r.columnTypesByName()
+
+	return MetaRow{Row: r.Row(), Columns: cols, Location: r.cfg.getLocation()}, err
+}
+
+// FetchMetaRows reads every row, as FetchRowsE does, along with the types of
+// their columns
+func (r QueryResult) FetchMetaRows() ([]MetaRow, error) {
+	cols, err := r.columnTypesByName()
+
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+
+	rows, err := r.FetchRowsE()
+
+	m := make([]MetaRow, len(rows))
+	loc := r.cfg.getLocation()
+
+	for i, row := range rows {
+		m[i] = MetaRow{Row: row, Columns: cols, Location: loc}
+	}
+
+	return m, err
+}
+
+// Type returns the type of the column col
+func (r MetaRow) Type(col string) (ColumnType, bool) {
+	t, ok := r.Columns[col]
+
+	return t, ok
+}
+
+// Time converts the value of col as Row.Time does, in Location.  Text of a
+// column whose declared type is a date or time type is parsed with the
+// layouts of that type alone; only text of other columns is tried against
+// every format Row.Time knows.
+func (r MetaRow) Time(col string) (time.Time, error) {
+	return convertCol(r.Row, col, r.converter(col).Time)
+}
+
+// Date converts the value of col as Row.Date does, in Location, parsing text
+// as Time does
+func (r MetaRow) Date(col string) (time.Time, error) {
+	return convertCol(r.Row, col, r.converter(col).Date)
+}
+
+// converter returns the Converter for the times of col
+func (r MetaRow) converter(col string) Converter {
+	return Converter{
+		Location: r.Location,
+		layouts:  typeLayouts[r.Columns[col].DatabaseType],
+	}
+}
+
+var (
+	dateLayouts     = []string{"2006-01-02"}
+	datetimeLayouts = []string{"2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999"}
+)
+
+// localTypes holds the date and time types whose values have no zone
+var localTypes = map[string]bool{
+	"DATE":          true,
+	"DATETIME":      true,
+	"DATETIME2":     true,
+	"SMALLDATETIME": true,
+	"TIMESTAMP":     true,
+}
+
+// localTimes returns the Converter reading the text of each column of types
+// that is of a date or time type without a zone as a time in loc, or nil when
+// there is no such column
+func localTimes(types []ColumnType, loc *time.Location) []*Converter {
+	var times []*Converter
+
+	for i, t := range types {
+		if !localTypes[t.DatabaseType] {
+			continue
+		}
+
+		if times == nil {
+			times = make([]*Converter, len(types))
+		}
+
+		times[i] = &Converter{Location: loc, layouts: typeLayouts[t.DatabaseType]}
+	}
+
+	return times
+}
+
+// localTime returns the text v as a time.Time, or v itself when it is not
+// text of one of the layouts of c
+func (c *Converter) localTime(v interface{}) interface{} {
+	switch v.(type) {
+	case []byte, string:
+		if t, err := c.Time(v); err == nil {
+			return t
+		}
+	}
+
+	return v
+}
+
+// typeLayouts holds the layouts of the text of each date and time type
+var typeLayouts = map[string][]string{
+	"DATE":          dateLayouts,
+	"DATETIME":      datetimeLayouts,
+	"DATETIME2":     datetimeLayouts,
+	"SMALLDATETIME": datetimeLayouts,
+	"TIMESTAMP":     datetimeLayouts,
+	"TIMESTAMPTZ": {
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999-07",
+		time.RFC3339Nano,
+	},
+	"DATETIMEOFFSET": {"2006-01-02 15:04:05.999999999 -07:00"},
+	"TIME":           {"15:04:05.999999999"},
+	"TIMETZ":         {"15:04:05.999999999Z07:00", "15:04:05.999999999-07"},
+}
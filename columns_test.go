@@ -0,0 +1,232 @@
+package gdo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestColumnTypes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "a"))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)))
+
+	g := New(db)
+
+	r, err := g.Query(NewStatement("SELECT id, name FROM Foo"))
+	assert.NoError(t, err)
+
+	types, err := r.ColumnTypes()
+	assert.NoError(t, err)
+
+	if assert.Len(t, types, 2) {
+		assert.Equal(t, "id", types[0].Name)
+		assert.Equal(t, "name", types[1].Name)
+	}
+
+	assert.True(t, r.Next())
+
+	row, err := r.MetaRow()
+	assert.NoError(t, err)
+	assert.Equal(t, Row{"id": int64(1), "name": "a"}, row.Row)
+
+	ct, ok := row.Type("name")
+	assert.True(t, ok)
+	assert.Equal(t, types[1], ct)
+
+	assert.False(t, r.Next())
+
+	// the types are still known once the rows are closed
+	types2, err := r.ColumnTypes()
+	assert.NoError(t, err)
+	assert.Equal(t, types, types2)
+
+	r, err = g.Query(NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	rows, err := r.FetchMetaRows()
+	assert.NoError(t, err)
+
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, Row{"id": int64(2)}, rows[1].Row)
+		assert.Contains(t, rows[1].Columns, "id")
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMetaRow(t *testing.T) {
+	row := MetaRow{
+		Row: Row{
+			"date":     []byte("2024-03-09"),
+			"datetime": []byte("2024-03-09 14:05:06.5"),
+			"price":    []byte("12.50"),
+		},
+		Columns: map[string]ColumnType{
+			"date":     {Name: "date", DatabaseType: "DATE"},
+			"datetime": {Name: "datetime", DatabaseType: "DATETIME"},
+			"price":    {Name: "price", DatabaseType: "DECIMAL", Precision: 10, Scale: 2, DecimalSizeKnown: true},
+		},
+	}
+
+	d, err := row.Time("date")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), d)
+
+	dt, err := row.Time("datetime")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 14, 5, 6, 500000000, time.UTC), dt)
+
+	ct, ok := row.Type("price")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), ct.Scale)
+
+	_, err = row.Time("missing")
+	assert.Equal(t, ErrColNotFound, err)
+}
+
+func TestMetaRowTypeLayouts(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	at := time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC)
+
+	cases := map[string]struct {
+		dbType string
+		text   string
+		want   time.Time
+		err    error
+	}{
+		"date":                {dbType: "DATE", text: "2024-03-09", want: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)},
+		"date with time":      {dbType: "DATE", text: "2024-03-09 14:05:06", err: ErrCannotConvert},
+		"datetime":            {dbType: "DATETIME", text: "2024-03-09 14:05:06", want: at},
+		"datetime t":          {dbType: "DATETIME", text: "2024-03-09T14:05:06", want: at},
+		"datetime date":       {dbType: "DATETIME", text: "2024-03-09", err: ErrCannotConvert},
+		"timestamp with zone": {dbType: "TIMESTAMP", text: "2024-03-09 09:05:06-05", err: ErrCannotConvert},
+		"timestamptz":         {dbType: "TIMESTAMPTZ", text: "2024-03-09 09:05:06-05", want: at.In(est)},
+		"datetimeoffset":      {dbType: "DATETIMEOFFSET", text: "2024-03-09 09:05:06.5 -05:00", want: at.Add(500 * time.Millisecond)},
+		"time":                {dbType: "TIME", text: "14:05:06", want: time.Date(0, 1, 1, 14, 5, 6, 0, time.UTC)},
+		"time date":           {dbType: "TIME", text: "2024-03-09", err: ErrCannotConvert},
+		"timetz":              {dbType: "TIMETZ", text: "09:05:06-05", want: time.Date(0, 1, 1, 9, 5, 6, 0, est)},
+		"unknown type":        {dbType: "TEXT", text: "2024-03-09 09:05:06-05", want: at},
+		"no type":             {text: "2024-03-09T14:05:06Z", want: at},
+	}
+
+	for name, c := range cases {
+		row := MetaRow{
+			Row:     Row{"c": []byte(c.text)},
+			Columns: map[string]ColumnType{"c": {Name: "c", DatabaseType: c.dbType}},
+		}
+
+		v, err := row.Time("c")
+
+		if c.err != nil {
+			assert.Equal(t, c.err, err, name)
+			continue
+		}
+
+		if assert.NoError(t, err, name) {
+			assert.True(t, c.want.Equal(v), "%s: %v", name, v)
+		}
+	}
+
+	// values that are not text are read whatever the type
+	row := MetaRow{
+		Row:     Row{"c": at},
+		Columns: map[string]ColumnType{"c": {Name: "c", DatabaseType: "DATE"}},
+	}
+
+	d, err := row.Date("c")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), d)
+}
+
+func TestMetaRowLocation(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	est := time.FixedZone("EST", -5*60*60)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"at"}).AddRow([]byte("2024-03-09 14:05:06")))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"at"}).AddRow([]byte("2024-03-09 14:05:06")))
+
+	g := New(db, WithLocation(est))
+	assert.Equal(t, est, g.Location())
+	assert.Equal(t, time.UTC, New(db).Location())
+
+	r, err := g.Query(NewStatement("SELECT at FROM Foo"))
+	assert.NoError(t, err)
+
+	rows, err := r.FetchMetaRows()
+	assert.NoError(t, err)
+
+	if assert.Len(t, rows, 1) {
+		at, err := rows[0].Time("at")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 9, 14, 5, 6, 0, est), at)
+
+		d, err := rows[0].Date("at")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, est), d)
+
+		// sqlmock reports no column types, so the Row on its own reads the
+		// time as UTC
+		at, err = rows[0].Row.Time("at")
+		assert.NoError(t, err)
+		assert.Equal(t, time.UTC, at.Location())
+	}
+
+	r, err = g.Query(NewStatement("SELECT at FROM Foo"))
+	assert.NoError(t, err)
+
+	assert.True(t, r.Next())
+
+	row, err := r.MetaRow()
+	assert.NoError(t, err)
+	assert.Equal(t, est, row.Location)
+	assert.NoError(t, r.Close())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRowLocation(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	est := time.FixedZone("EST", -5*60*60)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"at", "on", "tz", "name"}).
+		AddRow([]byte("2024-03-09 14:05:06"), "2024-03-09", []byte("2024-03-09 14:05:06+00"), []byte("2024-03-09 14:05:06")))
+
+	r, err := New(db, WithLocation(est)).Query(NewStatement("SELECT at, on, tz, name FROM Foo"))
+	assert.NoError(t, err)
+
+	// as the driver would report them
+	r.cursor.scanner.times = localTimes([]ColumnType{
+		{Name: "at", DatabaseType: "DATETIME"},
+		{Name: "on", DatabaseType: "DATE"},
+		{Name: "tz", DatabaseType: "TIMESTAMPTZ"},
+		{Name: "name", DatabaseType: "VARCHAR"},
+	}, est)
+
+	assert.True(t, r.Next())
+
+	row := r.Row()
+
+	at, err := row.Time("at")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 14, 5, 6, 0, est), at)
+
+	on, err := row.Date("on")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, est), on)
+
+	// zoned and non time columns are left as the driver returned them
+	assert.Equal(t, []byte("2024-03-09 14:05:06+00"), row["tz"])
+	assert.Equal(t, []byte("2024-03-09 14:05:06"), row["name"])
+
+	assert.NoError(t, r.Close())
+	assert.Nil(t, localTimes([]ColumnType{{Name: "name", DatabaseType: "TEXT"}}, est))
+}
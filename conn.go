@@ -0,0 +1,61 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Conn is a single connection taken from the pool of a GDO.  Statements run
+// on a Conn share one database session, which is what temporary tables and
+// session variables set with SET need.  Close returns it to the pool.
+type Conn struct {
+	*sql.Conn
+	cfg *config
+}
+
+// Conn reserves a connection from the pool
+func (g GDO) Conn(ctx context.Context) (Conn, error) {
+	c, err := g.DB.Conn(ctx)
+
+	return Conn{Conn: c, cfg: g.cfg}, err
+}
+
+func (c Conn) BeginTx() (Transaction, error) {
+	return c.BeginTxContext(context.Background(), nil)
+}
+
+func (c Conn) BeginTxContext(ctx context.Context, opts *sql.TxOptions) (Transaction, error) {
+	return beginTx(ctx, c.Conn, c.cfg, opts)
+}
+
+func (c Conn) Prepare(query string) (*PreparedStatement, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c Conn) PrepareContext(ctx context.Context, query string) (*PreparedStatement, error) {
+	return prepareContext(ctx, c.Conn, c.cfg, query)
+}
+
+func (c Conn) Exec(s *Statement) (ExecResult, error) {
+	return c.ExecContext(context.Background(), s)
+}
+
+func (c Conn) ExecContext(ctx context.Context, s *Statement) (ExecResult, error) {
+	return doExecCtx(c.Conn.ExecContext, ctx, c.cfg, s)
+}
+
+func (c Conn) Query(s *Statement) (QueryResult, error) {
+	return c.QueryContext(context.Background(), s)
+}
+
+func (c Conn) QueryContext(ctx context.Context, s *Statement) (QueryResult, error) {
+	return doQueryCtx(c.Conn.QueryContext, ctx, c.cfg, s)
+}
+
+func (c Conn) QueryRow(s *Statement) QueryRowResult {
+	return c.QueryRowContext(context.Background(), s)
+}
+
+func (c Conn) QueryRowContext(ctx context.Context, s *Statement) QueryRowResult {
+	return doQueryRowCtx(c.Conn.QueryContext, ctx, c.cfg, s)
+}
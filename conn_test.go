@@ -0,0 +1,65 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func countFoo(ctx context.Context, q Querier, bar string) (int, error) {
+	stmt := NewStatement("SELECT COUNT(*) AS c FROM Foo WHERE bar = :bar:")
+	stmt.BindNamedArg(sql.Named("bar", bar))
+
+	r := q.QueryRowContext(ctx, stmt)
+
+	if r.LastError() != nil {
+		return 0, r.LastError()
+	}
+
+	return r.FetchRow().Int("c")
+}
+
+func TestQuerier(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	query := regexp.QuoteMeta("SELECT COUNT(*) AS c FROM Foo WHERE bar = ?")
+
+	mock.ExpectQuery(query).WithArgs("a").WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(int64(1)))
+	mock.ExpectBegin()
+	mock.ExpectQuery(query).WithArgs("b").WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(int64(2)))
+	mock.ExpectCommit()
+	mock.ExpectExec("CREATE TEMPORARY TABLE Tmp").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(query).WithArgs("c").WillReturnRows(sqlmock.NewRows([]string{"c"}).AddRow(int64(3)))
+
+	ctx := context.Background()
+	g := New(db)
+
+	n, err := countFoo(ctx, g, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	tx, _ := g.BeginTx()
+
+	n, err = countFoo(ctx, tx, "b")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.NoError(t, tx.Commit())
+
+	conn, err := g.Conn(ctx)
+	assert.NoError(t, err)
+
+	_, err = conn.ExecContext(ctx, NewStatement("CREATE TEMPORARY TABLE Tmp (id INT)"))
+	assert.NoError(t, err)
+
+	n, err = countFoo(ctx, conn, "c")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.NoError(t, conn.Close())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,732 @@
+package gdo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrOverflow is returned when a value does not fit the type it is converted
+// to.  It wraps ErrCannotConvert.
+var ErrOverflow = fmt.Errorf("%w: value out of range", ErrCannotConvert)
+
+// Converter converts the values a driver returns to Go types.  Integers,
+// floats, booleans, strings, []byte and time.Time are accepted, along with
+// types whose underlying type is one of these, and nil converts to ErrNull.
+// Text is parsed as a number for the numeric conversions.  The accessors of
+// Row use a lenient Converter.
+type Converter struct {
+	// Strict only allows conversions that keep the value exactly: a float
+	// must be whole to become an integer, an integer must be exact as a
+	// float, only 0 and 1 become booleans, text is not trimmed, booleans and
+	// times do not become numbers and numbers do not become times.  A lenient
+	// Converter truncates floats to integers, reads booleans as 1 and 0, times
+	// as Unix seconds and back, and text such as "yes" and "off" as booleans.
+	Strict bool
+	// Location is the time zone of Unix times and of times read from text
+	// without one.  The default is UTC.
+	Location *time.Location
+
+	// layouts, when set, are the only layouts text is parsed with by Time
+	layouts []string
+}
+
+// lenient is the Converter of the accessors of Row
+var lenient Converter
+
+// sourceKind is the kind of value a conversion starts from
+type sourceKind int
+
+const (
+	srcInt sourceKind = iota
+	srcUint
+	srcFloat
+	srcBool
+	srcText
+	srcTime
+	numSourceKinds
+)
+
+// source is a value reduced to its sourceKind
+type source struct {
+	kind sourceKind
+	i    int64
+	u    uint64
+	f    float64
+	bits int // of f
+	b    bool
+	s    string
+	t    time.Time
+}
+
+// conversions holds, for each sourceKind, the conversion of a value of that
+// kind to T, or nil when there is none
+type conversions[T any] [numSourceKinds]func(c Converter, s source) (T, error)
+
+func (t *conversions[T]) convert(c Converter, s source) (T, error) {
+	fn := t[s.kind]
+
+	if fn == nil {
+		var zero T
+		return zero, ErrCannotConvert
+	}
+
+	return fn(c, s)
+}
+
+// The tables for the numeric types have no srcText entries, as text is parsed
+// into a number by Converter.number first.
+var toInt64 = conversions[int64]{
+	srcInt: func(c Converter, s source) (int64, error) {
+		return s.i, nil
+	},
+	srcUint: func(c Converter, s source) (int64, error) {
+		if s.u > math.MaxInt64 {
+			return 0, ErrOverflow
+		}
+
+		return int64(s.u), nil
+	},
+	srcFloat: func(c Converter, s source) (int64, error) {
+		t, err := c.whole(s.f)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if t < math.MinInt64 || t >= math.MaxInt64 {
+			return 0, ErrOverflow
+		}
+
+		return int64(t), nil
+	},
+	srcBool: func(c Converter, s source) (int64, error) {
+		if c.Strict {
+			return 0, ErrCannotConvert
+		}
+
+		if s.b {
+			return 1, nil
+		}
+
+		return 0, nil
+	},
+	srcTime: func(c Converter, s source) (int64, error) {
+		if c.Strict {
+			return 0, ErrCannotConvert
+		}
+
+		return s.t.Unix(), nil
+	},
+}
+
+var toUint64 = conversions[uint64]{
+	srcInt: func(c Converter, s source) (uint64, error) {
+		if s.i < 0 {
+			return 0, ErrOverflow
+		}
+
+		return uint64(s.i), nil
+	},
+	srcUint: func(c Converter, s source) (uint64, error) {
+		return s.u, nil
+	},
+	srcFloat: func(c Converter, s source) (uint64, error) {
+		t, err := c.whole(s.f)
+
+		if err != nil {
+			return 0, err
+		}
+
+		if t < 0 || t >= math.MaxUint64 {
+			return 0, ErrOverflow
+		}
+
+		return uint64(t), nil
+	},
+	srcBool: func(c Converter, s source) (uint64, error) {
+		i, err := toInt64.convert(c, s)
+
+		return uint64(i), err
+	},
+	srcTime: func(c Converter, s source) (uint64, error) {
+		i, err := toInt64.convert(c, s)
+
+		if err == nil && i < 0 {
+			return 0, ErrOverflow
+		}
+
+		return uint64(i), err
+	},
+}
+
+var toFloat64 = conversions[float64]{
+	srcInt: func(c Converter, s source) (float64, error) {
+		f := float64(s.i)
+
+		if c.Strict && (f >= math.MaxInt64 || int64(f) != s.i) {
+			return 0, ErrCannotConvert
+		}
+
+		return f, nil
+	},
+	srcUint: func(c Converter, s source) (float64, error) {
+		f := float64(s.u)
+
+		if c.Strict && (f >= math.MaxUint64 || uint64(f) != s.u) {
+			return 0, ErrCannotConvert
+		}
+
+		return f, nil
+	},
+	srcFloat: func(c Converter, s source) (float64, error) {
+		return s.f, nil
+	},
+	srcBool: func(c Converter, s source) (float64, error) {
+		i, err := toInt64.convert(c, s)
+
+		return float64(i), err
+	},
+}
+
+var toBool = conversions[bool]{
+	srcInt: func(c Converter, s source) (bool, error) {
+		return c.truth(s.i == 0, s.i == 1)
+	},
+	srcUint: func(c Converter, s source) (bool, error) {
+		return c.truth(s.u == 0, s.u == 1)
+	},
+	srcFloat: func(c Converter, s source) (bool, error) {
+		return c.truth(s.f == 0, s.f == 1)
+	},
+	srcBool: func(c Converter, s source) (bool, error) {
+		return s.b, nil
+	},
+	srcText: func(c Converter, s source) (bool, error) {
+		// a BIT(1) column as MySQL returns it
+		switch s.s {
+		case "\x00":
+			return false, nil
+		case "\x01":
+			return true, nil
+		}
+
+		if c.Strict {
+			b, err := strconv.ParseBool(s.s)
+
+			if err != nil {
+				return false, ErrCannotConvert
+			}
+
+			return b, nil
+		}
+
+		switch strings.ToLower(strings.TrimSpace(s.s)) {
+		case "1", "t", "true", "y", "yes", "on":
+			return true, nil
+		case "0", "f", "false", "n", "no", "off":
+			return false, nil
+		}
+
+		n, err := c.number(s, 64)
+
+		if err != nil {
+			return false, ErrCannotConvert
+		}
+
+		return n.i != 0 || n.u != 0 || n.f != 0, nil
+	},
+}
+
+var toString = conversions[string]{
+	srcInt: func(c Converter, s source) (string, error) {
+		return strconv.FormatInt(s.i, 10), nil
+	},
+	srcUint: func(c Converter, s source) (string, error) {
+		return strconv.FormatUint(s.u, 10), nil
+	},
+	srcFloat: func(c Converter, s source) (string, error) {
+		return strconv.FormatFloat(s.f, 'f', -1, s.bits), nil
+	},
+	srcBool: func(c Converter, s source) (string, error) {
+		return strconv.FormatBool(s.b), nil
+	},
+	srcText: func(c Converter, s source) (string, error) {
+		return s.s, nil
+	},
+	srcTime: func(c Converter, s source) (string, error) {
+		return s.t.Format(time.RFC3339Nano), nil
+	},
+}
+
+var toBytes = conversions[[]byte]{
+	srcInt:   formatBytes,
+	srcUint:  formatBytes,
+	srcFloat: formatBytes,
+	srcBool:  formatBytes,
+	srcText: func(c Converter, s source) ([]byte, error) {
+		return []byte(s.s), nil
+	},
+	srcTime: formatBytes,
+}
+
+// formatBytes writes s as String does, unless c is strict
+func formatBytes(c Converter, s source) ([]byte, error) {
+	if c.Strict {
+		return nil, ErrCannotConvert
+	}
+
+	text, err := toString.convert(c, s)
+
+	return []byte(text), err
+}
+
+var toTime = conversions[time.Time]{
+	srcInt: func(c Converter, s source) (time.Time, error) {
+		if c.Strict {
+			return time.Time{}, ErrCannotConvert
+		}
+
+		return time.Unix(s.i, 0).In(c.location()), nil
+	},
+	srcUint: func(c Converter, s source) (time.Time, error) {
+		if c.Strict {
+			return time.Time{}, ErrCannotConvert
+		}
+
+		i, err := toInt64.convert(c, s)
+
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		return time.Unix(i, 0).In(c.location()), nil
+	},
+	srcText: func(c Converter, s source) (time.Time, error) {
+		return c.parseTime(s.s)
+	},
+	srcTime: func(c Converter, s source) (time.Time, error) {
+		return s.t, nil
+	},
+}
+
+var toDuration = conversions[time.Duration]{
+	srcText: func(c Converter, s source) (time.Duration, error) {
+		text := s.s
+
+		if !c.Strict {
+			text = strings.TrimSpace(text)
+		}
+
+		return parseClock(text)
+	},
+	srcTime: func(c Converter, s source) (time.Duration, error) {
+		h, m, sec := s.t.Clock()
+
+		return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute +
+			time.Duration(sec)*time.Second + time.Duration(s.t.Nanosecond()), nil
+	},
+}
+
+// timeLayouts are the layouts text is parsed with by Converter.Time, in the
+// order they are tried
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00", // SQLite
+	"2006-01-02 15:04:05.999999999-07",    // PostgreSQL timestamptz
+	"2006-01-02T15:04:05.999999999",       // SQLite
+	"2006-01-02 15:04:05.999999999",       // MySQL DATETIME, PostgreSQL timestamp
+	"2006-01-02T15:04",                    // SQLite
+	"2006-01-02 15:04",                    // SQLite
+	"2006-01-02",                          // DATE
+	"15:04:05.999999999",                  // TIME
+}
+
+// Int64 converts v to an int64
+func (c Converter) Int64(v interface{}) (int64, error) {
+	s, err := c.numberOf(v, 64)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return toInt64.convert(c, s)
+}
+
+// Int32 converts v to an int32
+func (c Converter) Int32(v interface{}) (int32, error) {
+	i, err := c.Int64(v)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, ErrOverflow
+	}
+
+	return int32(i), nil
+}
+
+// Int converts v to an int
+func (c Converter) Int(v interface{}) (int, error) {
+	i, err := c.Int64(v)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if i < math.MinInt || i > math.MaxInt {
+		return 0, ErrOverflow
+	}
+
+	return int(i), nil
+}
+
+// Uint64 converts v to a uint64
+func (c Converter) Uint64(v interface{}) (uint64, error) {
+	s, err := c.numberOf(v, 64)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return toUint64.convert(c, s)
+}
+
+// Float64 converts v to a float64
+func (c Converter) Float64(v interface{}) (float64, error) {
+	s, err := c.numberOf(v, 64)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return toFloat64.convert(c, s)
+}
+
+// Float32 converts v to a float32
+func (c Converter) Float32(v interface{}) (float32, error) {
+	s, err := c.numberOf(v, 32)
+
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := toFloat64.convert(c, s)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+		return 0, ErrOverflow
+	}
+
+	if c.Strict && float64(float32(f)) != f && !math.IsNaN(f) {
+		return 0, ErrCannotConvert
+	}
+
+	return float32(f), nil
+}
+
+// Bool converts v to a bool
+func (c Converter) Bool(v interface{}) (bool, error) {
+	s, err := sourceOf(v)
+
+	if err != nil {
+		return false, err
+	}
+
+	return toBool.convert(c, s)
+}
+
+// String converts v to a string.  Floats are written without an exponent and
+// times as RFC 3339 with nanoseconds.
+func (c Converter) String(v interface{}) (string, error) {
+	s, err := sourceOf(v)
+
+	if err != nil {
+		return "", err
+	}
+
+	return toString.convert(c, s)
+}
+
+// Bytes converts v to a []byte.  A []byte is returned as it is.  A lenient
+// Converter writes numbers, booleans and times as String does.
+func (c Converter) Bytes(v interface{}) ([]byte, error) {
+	if b, ok := v.([]byte); ok {
+		return b, nil
+	}
+
+	s, err := sourceOf(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return toBytes.convert(c, s)
+}
+
+// Time converts v to a time.Time.  Text is parsed as RFC 3339, as the
+// DATETIME, DATE and TIME of MySQL, the timestamp and timestamptz of
+// PostgreSQL and the formats of SQLite, and times read from text with a zone
+// keep it.
+func (c Converter) Time(v interface{}) (time.Time, error) {
+	s, err := sourceOf(v)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return toTime.convert(c, s)
+}
+
+// Date converts v to a time.Time as Time does and returns midnight of its day
+func (c Converter) Date(v interface{}) (time.Time, error) {
+	t, err := c.Time(v)
+
+	if err != nil {
+		return t, err
+	}
+
+	y, m, d := t.Date()
+
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location()), nil
+}
+
+// Duration converts v to a time.Duration.  Text is parsed as a TIME column
+// such as "-838:59:59.5", and a time.Time is read as the time since midnight.
+func (c Converter) Duration(v interface{}) (time.Duration, error) {
+	if d, ok := v.(time.Duration); ok {
+		return d, nil
+	}
+
+	s, err := sourceOf(v)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return toDuration.convert(c, s)
+}
+
+// numberOf reduces v to a source, parsing text as a number
+func (c Converter) numberOf(v interface{}, bitSize int) (source, error) {
+	s, err := sourceOf(v)
+
+	if err != nil {
+		return s, err
+	}
+
+	return c.number(s, bitSize)
+}
+
+// number parses a srcText source as an integer, or else as a float of
+// bitSize bits, and returns any other source as it is
+func (c Converter) number(s source, bitSize int) (source, error) {
+	if s.kind != srcText {
+		return s, nil
+	}
+
+	text := s.s
+
+	if !c.Strict {
+		text = strings.TrimSpace(text)
+	}
+
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return source{kind: srcInt, i: i}, nil
+	}
+
+	if u, err := strconv.ParseUint(text, 10, 64); err == nil {
+		return source{kind: srcUint, u: u}, nil
+	}
+
+	f, err := strconv.ParseFloat(text, bitSize)
+
+	if errors.Is(err, strconv.ErrRange) {
+		return source{}, ErrOverflow
+	}
+
+	if err != nil {
+		return source{}, ErrCannotConvert
+	}
+
+	return source{kind: srcFloat, f: f, bits: bitSize}, nil
+}
+
+// whole returns f truncated toward zero, which only a lenient Converter does
+// to a float that is not whole
+func (c Converter) whole(f float64) (float64, error) {
+	if math.IsNaN(f) {
+		return 0, ErrCannotConvert
+	}
+
+	t := math.Trunc(f)
+
+	if c.Strict && t != f {
+		return 0, ErrCannotConvert
+	}
+
+	return t, nil
+}
+
+// truth returns the bool for a number that is zero or one.  A lenient
+// Converter reads any other number as true.
+func (c Converter) truth(zero, one bool) (bool, error) {
+	if c.Strict && !zero && !one {
+		return false, ErrCannotConvert
+	}
+
+	return !zero, nil
+}
+
+func (c Converter) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+
+	return c.Location
+}
+
+// parseTime parses text with the first of its layouts, or else of
+// timeLayouts, that fits it
+func (c Converter) parseTime(text string) (time.Time, error) {
+	if !c.Strict {
+		text = strings.TrimSpace(text)
+	}
+
+	layouts := c.layouts
+
+	if layouts == nil {
+		layouts = timeLayouts
+	}
+
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, text, c.location()); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, ErrCannotConvert
+}
+
+// parseClock parses a duration written as [-]hours:minutes[:seconds[.frac]]
+func parseClock(text string) (time.Duration, error) {
+	neg := strings.HasPrefix(text, "-")
+
+	parts := strings.Split(strings.TrimPrefix(text, "-"), ":")
+
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, ErrCannotConvert
+	}
+
+	var sec, frac string
+
+	if len(parts) == 3 {
+		sec, frac, _ = strings.Cut(parts[2], ".")
+	}
+
+	h, err := parseDigits(parts[0], 0)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if h > int64(math.MaxInt64/time.Hour) {
+		return 0, ErrOverflow
+	}
+
+	m, err := parseDigits(parts[1], 60)
+
+	if err != nil {
+		return 0, err
+	}
+
+	var sc, ns int64
+
+	if sec != "" {
+		if sc, err = parseDigits(sec, 60); err != nil {
+			return 0, err
+		}
+	}
+
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+
+		if ns, err = parseDigits(frac+strings.Repeat("0", 9-len(frac)), 0); err != nil {
+			return 0, err
+		}
+	}
+
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(sc)*time.Second + time.Duration(ns)
+
+	if neg {
+		d = -d
+	}
+
+	return d, nil
+}
+
+// parseDigits parses a non-empty string of decimal digits less than limit,
+// if limit is not 0
+func parseDigits(s string, limit int64) (int64, error) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return 0, ErrCannotConvert
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+
+	if err != nil {
+		return 0, ErrOverflow
+	}
+
+	if limit != 0 && n >= limit {
+		return 0, ErrCannotConvert
+	}
+
+	return n, nil
+}
+
+// sourceOf reduces v to a source by its underlying type
+func sourceOf(v interface{}) (source, error) {
+	switch v := v.(type) {
+	case nil:
+		return source{}, ErrNull
+	case []byte:
+		return source{kind: srcText, s: string(v)}, nil
+	case time.Time:
+		return source{kind: srcTime, t: v}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return source{kind: srcInt, i: rv.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return source{kind: srcUint, u: rv.Uint()}, nil
+	case reflect.Float32:
+		return source{kind: srcFloat, f: rv.Float(), bits: 32}, nil
+	case reflect.Float64:
+		return source{kind: srcFloat, f: rv.Float(), bits: 64}, nil
+	case reflect.Bool:
+		return source{kind: srcBool, b: rv.Bool()}, nil
+	case reflect.String:
+		return source{kind: srcText, s: rv.String()}, nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return source{kind: srcText, s: string(rv.Bytes())}, nil
+		}
+	}
+
+	return source{}, ErrCannotConvert
+}
@@ -0,0 +1,415 @@
+package gdo
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type convertCase struct {
+	v      interface{}
+	strict bool
+	want   interface{}
+	err    error
+}
+
+type myInt int16
+
+func TestConverterInt64(t *testing.T) {
+	cases := map[string]convertCase{
+		"int":                {v: 1, want: int64(1)},
+		"int8":               {v: int8(-8), want: int64(-8)},
+		"int16":              {v: int16(16), want: int64(16)},
+		"int32":              {v: int32(32), want: int64(32)},
+		"int64":              {v: int64(math.MaxInt64), want: int64(math.MaxInt64)},
+		"named int":          {v: myInt(5), want: int64(5)},
+		"uint":               {v: uint(1), want: int64(1)},
+		"uint8":              {v: uint8(255), want: int64(255)},
+		"uint64":             {v: uint64(math.MaxInt64), want: int64(math.MaxInt64)},
+		"uint64 overflow":    {v: uint64(math.MaxUint64), err: ErrOverflow},
+		"float32":            {v: float32(2), want: int64(2)},
+		"float64":            {v: 2.0, want: int64(2)},
+		"float truncates":    {v: -2.7, want: int64(-2)},
+		"float strict":       {v: 2.7, strict: true, err: ErrCannotConvert},
+		"float whole":        {v: 2.0, strict: true, want: int64(2)},
+		"float overflow":     {v: 1e19, err: ErrOverflow},
+		"float underflow":    {v: -1e19, err: ErrOverflow},
+		"float min":          {v: float64(math.MinInt64), want: int64(math.MinInt64)},
+		"float inf":          {v: math.Inf(1), err: ErrOverflow},
+		"float nan":          {v: math.NaN(), err: ErrCannotConvert},
+		"bool":               {v: true, want: int64(1)},
+		"bool strict":        {v: true, strict: true, err: ErrCannotConvert},
+		"bytes":              {v: []byte("-42"), want: int64(-42)},
+		"string":             {v: "42", want: int64(42)},
+		"text spaces":        {v: " 42 ", want: int64(42)},
+		"text spaces strict": {v: " 42 ", strict: true, err: ErrCannotConvert},
+		"text float":         {v: []byte("12.5"), want: int64(12)},
+		"text float strict":  {v: []byte("12.5"), strict: true, err: ErrCannotConvert},
+		"text overflow":      {v: []byte("9223372036854775808"), err: ErrOverflow},
+		"text huge":          {v: []byte("1e400"), err: ErrOverflow},
+		"text invalid":       {v: []byte("abc"), err: ErrCannotConvert},
+		"text empty":         {v: []byte(""), err: ErrCannotConvert},
+		"time":               {v: time.Unix(1500000000, 0), want: int64(1500000000)},
+		"time strict":        {v: time.Unix(1500000000, 0), strict: true, err: ErrCannotConvert},
+		"nil":                {v: nil, err: ErrNull},
+		"other":              {v: struct{}{}, err: ErrCannotConvert},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Int64(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterInt32(t *testing.T) {
+	cases := map[string]convertCase{
+		"int64":          {v: int64(math.MaxInt32), want: int32(math.MaxInt32)},
+		"int64 min":      {v: int64(math.MinInt32), want: int32(math.MinInt32)},
+		"int64 overflow": {v: int64(math.MaxInt32 + 1), err: ErrOverflow},
+		"int64 under":    {v: int64(math.MinInt32 - 1), err: ErrOverflow},
+		"uint32":         {v: uint32(math.MaxUint32), err: ErrOverflow},
+		"text":           {v: []byte("-7"), want: int32(-7)},
+		"text overflow":  {v: []byte("3000000000"), err: ErrOverflow},
+		"float":          {v: float32(3.9), want: int32(3)},
+		"nil":            {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Int32(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterUint64(t *testing.T) {
+	cases := map[string]convertCase{
+		"int":              {v: 7, want: uint64(7)},
+		"int negative":     {v: -1, err: ErrOverflow},
+		"uint64":           {v: uint64(math.MaxUint64), want: uint64(math.MaxUint64)},
+		"float":            {v: 7.9, want: uint64(7)},
+		"float strict":     {v: 7.9, strict: true, err: ErrCannotConvert},
+		"float negative":   {v: -1.5, err: ErrOverflow},
+		"float fraction":   {v: -0.5, want: uint64(0)},
+		"float overflow":   {v: 2e19, err: ErrOverflow},
+		"bool":             {v: true, want: uint64(1)},
+		"bool strict":      {v: false, strict: true, err: ErrCannotConvert},
+		"text max":         {v: []byte("18446744073709551615"), want: uint64(math.MaxUint64)},
+		"text overflow":    {v: []byte("18446744073709551616"), err: ErrOverflow},
+		"text negative":    {v: []byte("-5"), err: ErrOverflow},
+		"time":             {v: time.Unix(10, 0), want: uint64(10)},
+		"time before 1970": {v: time.Unix(-10, 0), err: ErrOverflow},
+		"nil":              {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Uint64(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterInt(t *testing.T) {
+	cases := map[string]convertCase{
+		"int":    {v: 3, want: 3},
+		"int64":  {v: int64(-3), want: -3},
+		"uint16": {v: uint16(3), want: 3},
+		"text":   {v: "3", want: 3},
+		"nil":    {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Int(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterFloat64(t *testing.T) {
+	cases := map[string]convertCase{
+		"int":              {v: 3, want: 3.0},
+		"int exact":        {v: int64(1 << 53), strict: true, want: float64(1 << 53)},
+		"int lossy":        {v: int64(1<<53 + 1), want: float64(1 << 53)},
+		"int lossy strict": {v: int64(1<<53 + 1), strict: true, err: ErrCannotConvert},
+		"int max strict":   {v: int64(math.MaxInt64), strict: true, err: ErrCannotConvert},
+		"uint":             {v: uint64(1 << 60), strict: true, want: float64(1 << 60)},
+		"uint max strict":  {v: uint64(math.MaxUint64), strict: true, err: ErrCannotConvert},
+		"float32":          {v: float32(0.5), want: 0.5},
+		"float64":          {v: 0.1, strict: true, want: 0.1},
+		"bool":             {v: true, want: 1.0},
+		"bool strict":      {v: true, strict: true, err: ErrCannotConvert},
+		"text":             {v: []byte("12.75"), want: 12.75},
+		"text int":         {v: []byte("12"), want: 12.0},
+		"text exponent":    {v: []byte("1.5e3"), want: 1500.0},
+		"text overflow":    {v: []byte("1e400"), err: ErrOverflow},
+		"text invalid":     {v: []byte("1.2.3"), err: ErrCannotConvert},
+		"time":             {v: time.Unix(1, 0), err: ErrCannotConvert},
+		"nil":              {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Float64(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterFloat32(t *testing.T) {
+	cases := map[string]convertCase{
+		"float32":          {v: float32(0.1), strict: true, want: float32(0.1)},
+		"float64":          {v: 0.1, want: float32(0.1)},
+		"float64 strict":   {v: 0.1, strict: true, err: ErrCannotConvert},
+		"float64 exact":    {v: 0.5, strict: true, want: float32(0.5)},
+		"float64 overflow": {v: 1e39, err: ErrOverflow},
+		"inf":              {v: math.Inf(-1), want: float32(math.Inf(-1))},
+		"int lossy":        {v: 1<<24 + 1, strict: true, err: ErrCannotConvert},
+		"text":             {v: []byte("0.1"), strict: true, want: float32(0.1)},
+		"text overflow":    {v: []byte("1e39"), err: ErrOverflow},
+		"nil":              {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Float32(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterBool(t *testing.T) {
+	cases := map[string]convertCase{
+		"true":            {v: true, want: true},
+		"int 0":           {v: 0, strict: true, want: false},
+		"int 1":           {v: int8(1), strict: true, want: true},
+		"int 2":           {v: int64(2), want: true},
+		"int 2 strict":    {v: int64(2), strict: true, err: ErrCannotConvert},
+		"uint 1":          {v: uint8(1), strict: true, want: true},
+		"float 0":         {v: 0.0, strict: true, want: false},
+		"float half":      {v: 0.5, want: true},
+		"float strict":    {v: 0.5, strict: true, err: ErrCannotConvert},
+		"bit 0":           {v: []byte{0}, strict: true, want: false},
+		"bit 1":           {v: []byte{1}, strict: true, want: true},
+		"text 0":          {v: []byte("0"), strict: true, want: false},
+		"text 1":          {v: []byte("1"), strict: true, want: true},
+		"text true":       {v: "TRUE", strict: true, want: true},
+		"text yes":        {v: []byte(" Yes "), want: true},
+		"text yes strict": {v: []byte("yes"), strict: true, err: ErrCannotConvert},
+		"text off":        {v: "off", want: false},
+		"text number":     {v: []byte("2.5"), want: true},
+		"text zero":       {v: []byte("0.0"), want: false},
+		"text invalid":    {v: []byte("maybe"), err: ErrCannotConvert},
+		"time":            {v: time.Now(), err: ErrCannotConvert},
+		"nil":             {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Bool(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterString(t *testing.T) {
+	cases := map[string]convertCase{
+		"int":     {v: 65, want: "65"},
+		"int8":    {v: int8(-65), want: "-65"},
+		"int64":   {v: int64(1234567890123), want: "1234567890123"},
+		"uint64":  {v: uint64(math.MaxUint64), want: "18446744073709551615"},
+		"float32": {v: float32(0.1), want: "0.1"},
+		"float64": {v: 1e21, want: "1000000000000000000000"},
+		"bool":    {v: false, want: "false"},
+		"bytes":   {v: []byte("abc"), want: "abc"},
+		"string":  {v: "abc", strict: true, want: "abc"},
+		"time":    {v: time.Date(2024, 3, 9, 14, 5, 6, 5, time.UTC), want: "2024-03-09T14:05:06.000000005Z"},
+		"nil":     {v: nil, err: ErrNull},
+		"other":   {v: []int{1}, err: ErrCannotConvert},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.String(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterBytes(t *testing.T) {
+	cases := map[string]convertCase{
+		"bytes":       {v: []byte("abc"), strict: true, want: []byte("abc")},
+		"string":      {v: "abc", strict: true, want: []byte("abc")},
+		"raw":         {v: []uint8{1, 2}, want: []byte{1, 2}},
+		"int":         {v: 65, want: []byte("65")},
+		"float64":     {v: 2.5, want: []byte("2.5")},
+		"bool":        {v: true, want: []byte("true")},
+		"time":        {v: time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC), want: []byte("2024-03-09T14:05:06Z")},
+		"strict int":  {v: 65, strict: true, err: ErrCannotConvert},
+		"strict time": {v: time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC), strict: true, err: ErrCannotConvert},
+		"nil":         {v: nil, err: ErrNull},
+		"other":       {v: []int{1}, err: ErrCannotConvert},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Bytes(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestConverterTime(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	ist := time.FixedZone("", 5*60*60+30*60)
+	at := time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC)
+	atFrac := time.Date(2024, 3, 9, 14, 5, 6, 500000000, time.UTC)
+
+	cases := map[string]convertCase{
+		"time":               {v: at.In(est), strict: true, want: at.In(est)},
+		"unix":               {v: int64(at.Unix()), want: at},
+		"unix uint":          {v: uint32(at.Unix()), want: at},
+		"unix strict":        {v: int64(at.Unix()), strict: true, err: ErrCannotConvert},
+		"rfc3339":            {v: "2024-03-09T14:05:06Z", want: at},
+		"rfc3339 nano":       {v: []byte("2024-03-09T14:05:06.5Z"), want: atFrac},
+		"rfc3339 offset":     {v: []byte("2024-03-09T09:05:06-05:00"), want: at.In(est)},
+		"mysql datetime":     {v: []byte("2024-03-09 14:05:06"), want: at},
+		"mysql fraction":     {v: []byte("2024-03-09 14:05:06.500000"), want: atFrac},
+		"mysql date":         {v: []byte("2024-03-09"), want: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)},
+		"mysql time":         {v: []byte("14:05:06"), want: time.Date(0, 1, 1, 14, 5, 6, 0, time.UTC)},
+		"postgres tz":        {v: []byte("2024-03-09 09:05:06.5-05"), want: atFrac.In(est)},
+		"postgres tz minute": {v: []byte("2024-03-09 19:35:06+05:30"), want: at.In(ist)},
+		"postgres timestamp": {v: []byte("2024-03-09 14:05:06.5"), want: atFrac},
+		"sqlite t":           {v: "2024-03-09T14:05:06.5", want: atFrac},
+		"sqlite zone":        {v: "2024-03-09 14:05:06.5+00:00", want: atFrac},
+		"sqlite minutes":     {v: "2024-03-09 14:05", want: at.Truncate(time.Minute)},
+		"sqlite t minutes":   {v: "2024-03-09T14:05", want: at.Truncate(time.Minute)},
+		"spaces":             {v: " 2024-03-09 14:05:06 ", want: at},
+		"spaces strict":      {v: " 2024-03-09 14:05:06 ", strict: true, err: ErrCannotConvert},
+		"invalid":            {v: []byte("2024-13-09"), err: ErrCannotConvert},
+		"float":              {v: 1.5, err: ErrCannotConvert},
+		"bool":               {v: true, err: ErrCannotConvert},
+		"nil":                {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Time(c.v)
+
+		if c.err == nil && err == nil {
+			assert.True(t, c.want.(time.Time).Equal(v), "%s: %v", name, v)
+			continue
+		}
+
+		assertConverted(t, name, c, v, err)
+	}
+
+	// times without a zone are read in Location, others keep theirs
+	c := Converter{Location: est}
+
+	v, err := c.Time([]byte("2024-03-09 09:05:06"))
+	assert.NoError(t, err)
+	assert.Equal(t, at.In(est), v)
+	assert.Equal(t, est, v.Location())
+
+	v, err = c.Time(at.Unix())
+	assert.NoError(t, err)
+	assert.Equal(t, est, v.Location())
+
+	v, err = c.Time([]byte("2024-03-09T14:05:06Z"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, v.Location())
+}
+
+func TestConverterDate(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+
+	v, err := Converter{}.Date([]byte("2024-03-09 14:05:06"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), v)
+
+	v, err = Converter{Location: est}.Date([]byte("2024-03-09"))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, est), v)
+
+	v, err = Converter{}.Date(time.Date(2024, 3, 9, 23, 0, 0, 0, est))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, est), v)
+
+	_, err = Converter{}.Date(nil)
+	assert.Equal(t, ErrNull, err)
+}
+
+func TestConverterDuration(t *testing.T) {
+	cases := map[string]convertCase{
+		"time":           {v: []byte("14:05:06"), want: 14*time.Hour + 5*time.Minute + 6*time.Second},
+		"no seconds":     {v: "14:05", want: 14*time.Hour + 5*time.Minute},
+		"fraction":       {v: []byte("00:00:01.25"), want: 1250 * time.Millisecond},
+		"nanoseconds":    {v: []byte("00:00:00.0000000019"), want: time.Nanosecond},
+		"mysql max":      {v: []byte("838:59:59"), want: 838*time.Hour + 59*time.Minute + 59*time.Second},
+		"negative":       {v: []byte("-01:30:00"), want: -90 * time.Minute},
+		"spaces":         {v: " 01:00 ", want: time.Hour},
+		"spaces strict":  {v: " 01:00 ", strict: true, err: ErrCannotConvert},
+		"duration":       {v: 90 * time.Second, strict: true, want: 90 * time.Second},
+		"time of day":    {v: time.Date(0, 1, 1, 1, 2, 3, 4, time.UTC), want: time.Hour + 2*time.Minute + 3*time.Second + 4},
+		"minutes range":  {v: []byte("01:60:00"), err: ErrCannotConvert},
+		"seconds range":  {v: []byte("01:00:60"), err: ErrCannotConvert},
+		"hours overflow": {v: []byte("9999999:00:00"), err: ErrOverflow},
+		"one part":       {v: []byte("15"), err: ErrCannotConvert},
+		"four parts":     {v: []byte("1:2:3:4"), err: ErrCannotConvert},
+		"signed minutes": {v: []byte("01:-5"), err: ErrCannotConvert},
+		"empty fraction": {v: []byte("01:00:00."), want: time.Hour},
+		"int":            {v: int64(5), err: ErrCannotConvert},
+		"nil":            {v: nil, err: ErrNull},
+	}
+
+	for name, c := range cases {
+		v, err := Converter{Strict: c.strict}.Duration(c.v)
+		assertConverted(t, name, c, v, err)
+	}
+}
+
+func TestRowConvert(t *testing.T) {
+	r := Row{"id": []byte("12"), "big": uint64(math.MaxUint64), "n": int64(65), "f": []byte("2.5")}
+
+	i64, err := r.Int64("id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), i64)
+
+	i32, err := r.Int32("big")
+	assert.Equal(t, ErrOverflow, err)
+	assert.Equal(t, int32(0), i32)
+
+	u64, err := r.Uint64("big")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), u64)
+
+	s, err := r.String("n")
+	assert.NoError(t, err)
+	assert.Equal(t, "65", s)
+
+	b, err := Row{"text": "abc"}.Bytes("text")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("abc"), b)
+
+	f, err := r.Float64("f")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, f)
+
+	_, err = r.Uint64("missing")
+	assert.Equal(t, ErrColNotFound, err)
+
+	r = Row{"at": []byte("2024-03-09 14:05:06"), "took": []byte("00:01:30")}
+
+	tm, err := r.Time("at")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC), tm)
+
+	d, err := r.Date("at")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), d)
+
+	dur, err := r.Duration("took")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, dur)
+}
+
+func assertConverted(t *testing.T, name string, c convertCase, v interface{}, err error) {
+	t.Helper()
+
+	if c.err != nil {
+		assert.Equal(t, c.err, err, name)
+		assert.ErrorIs(t, err, ErrCannotConvert, name)
+		return
+	}
+
+	if assert.NoError(t, err, name) {
+		assert.Equal(t, c.want, v, name)
+	}
+}
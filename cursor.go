@@ -0,0 +1,186 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"iter"
+)
+
+var ErrNoCursor = errors.New("gdo: result was not returned by a query")
+
+// cursor is the state of a QueryResult being read a row at a time
+type cursor struct {
+	ctx     context.Context
+	rows    *sql.Rows
+	scanner *rowScanner
+	row     Row
+	err     error
+
+	// types and byName cache the column types of the result
+	types  []ColumnType
+	byName map[string]ColumnType
+}
+
+func newCursor(ctx context.Context, cfg *config, rows *sql.Rows, cols []string) *cursor {
+	c := &cursor{ctx: ctx, rows: rows, scanner: newRowScanner(cols)}
+
+	// times without a zone are read in the location of the GDO as the rows
+	// are scanned, as a Row does not know the GDO it was read with
+	if cfg != nil && cfg.location != nil {
+		if types, err := (QueryResult{Rows: rows, cursor: c}).ColumnTypes(); err == nil {
+			c.scanner.times = localTimes(types, cfg.location)
+		}
+	}
+
+	return c
+}
+
+// rowScanner scans rows into a Row, reusing its scan buffers from row to row
+type rowScanner struct {
+	cols   []string
+	values []interface{}
+	dest   []interface{}
+	// times holds the Converter of each column whose text is read as a time
+	// when scanned, or is nil when there are none
+	times []*Converter
+}
+
+func newRowScanner(cols []string) *rowScanner {
+	s := &rowScanner{
+		cols:   cols,
+		values: make([]interface{}, len(cols)),
+		dest:   make([]interface{}, len(cols)),
+	}
+
+	for i := range s.dest {
+		s.dest[i] = &s.values[i]
+	}
+
+	return s
+}
+
+func (s *rowScanner) scan(rows *sql.Rows) (Row, error) {
+	err := rows.Scan(s.dest...)
+
+	row := make(Row, len(s.cols))
+
+	for i, col := range s.cols {
+		row[col] = s.values[i]
+		s.values[i] = nil
+
+		if s.times != nil && s.times[i] != nil {
+			row[col] = s.times[i].localTime(row[col])
+		}
+	}
+
+	return row, err
+}
+
+// Next advances to the next row, returning false once there are no more rows,
+// an error occurs or the context of the query is done, after which the rows
+// are closed and Err reports why.  Rows are read one at a time, so a result
+// can be larger than memory.
+func (r QueryResult) Next() bool {
+	c := r.cursor
+
+	if c == nil || c.err != nil {
+		return false
+	}
+
+	if err := c.ctx.Err(); err != nil {
+		c.stop(err)
+		return false
+	}
+
+	if !c.rows.Next() {
+		c.stop(c.rows.Err())
+		return false
+	}
+
+	row, err := c.scanner.scan(c.rows)
+
+	if err != nil {
+		c.stop(err)
+		return false
+	}
+
+	c.row = row
+
+	return true
+}
+
+// Row returns the row Next advanced to.  Each call to Next makes a new Row,
+// so a Row may be kept after moving on.
+func (r QueryResult) Row() Row {
+	if r.cursor == nil {
+		return nil
+	}
+
+	return r.cursor.row
+}
+
+// Err returns the error that ended iteration with Next, or nil when the rows
+// were read to the end
+func (r QueryResult) Err() error {
+	if r.cursor == nil {
+		return ErrNoCursor
+	}
+
+	if r.cursor.err == errDone {
+		return nil
+	}
+
+	return r.cursor.err
+}
+
+// Close closes the rows, which is only needed when iteration is abandoned
+// before Next returns false
+func (r QueryResult) Close() error {
+	if r.cursor != nil {
+		r.cursor.stop(nil)
+	}
+
+	if r.Rows == nil {
+		return nil
+	}
+
+	return r.Rows.Close()
+}
+
+// All returns an iterator over the rows, for use with range.  An error ends
+// iteration, after being yielded with a nil Row.  The rows are closed when
+// the loop ends, including by break.
+func (r QueryResult) All() iter.Seq2[Row, error] {
+	return func(yield func(Row, error) bool) {
+		defer r.Close()
+
+		for r.Next() {
+			if !yield(r.Row(), nil) {
+				return
+			}
+		}
+
+		if err := r.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// errDone marks a cursor read to the end
+var errDone = errors.New("gdo: done")
+
+// stop ends iteration with err, or errDone when err is nil
+func (c *cursor) stop(err error) {
+	if c.err != nil {
+		return
+	}
+
+	if err == nil {
+		err = errDone
+	}
+
+	c.err = err
+	c.row = nil
+	c.rows.Close()
+}
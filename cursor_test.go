@@ -0,0 +1,124 @@
+package gdo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestQueryResultNext(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+		AddRow(int64(1), "a").
+		AddRow(int64(2), "b"))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).
+		AddRow(int64(1)).
+		AddRow(int64(2)).
+		RowError(1, fail))
+
+	g := New(db)
+
+	r, err := g.Query(NewStatement("SELECT id, name FROM Foo"))
+	assert.NoError(t, err)
+
+	var rows Rows
+
+	for r.Next() {
+		rows = append(rows, r.Row())
+	}
+
+	assert.NoError(t, r.Err())
+	assert.Equal(t, Rows{{"id": int64(1), "name": "a"}, {"id": int64(2), "name": "b"}}, rows)
+	assert.False(t, r.Next())
+	assert.Nil(t, r.Row())
+
+	r, err = g.Query(NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	assert.True(t, r.Next())
+	assert.Equal(t, Row{"id": int64(1)}, r.Row())
+	assert.False(t, r.Next())
+	assert.Equal(t, fail, r.Err())
+
+	assert.Equal(t, ErrNoCursor, QueryResult{}.Err())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryResultNextCanceled(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r, err := New(db).QueryContext(ctx, NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	assert.True(t, r.Next())
+	cancel()
+	assert.False(t, r.Next())
+	assert.Equal(t, context.Canceled, r.Err())
+}
+
+func TestQueryResultAll(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).
+		AddRow(int64(1)).
+		AddRow(int64(2)).
+		AddRow(int64(3)))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).
+		AddRow(int64(1)).
+		AddRow(int64(2)).
+		RowError(1, fail))
+
+	g := New(db)
+
+	r, err := g.Query(NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	var ids []int
+
+	for row, err := range r.All() {
+		assert.NoError(t, err)
+
+		id, _ := row.Int("id")
+		ids = append(ids, id)
+
+		if id == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []int{1, 2}, ids)
+
+	// breaking out of the loop closes the rows
+	_, err = r.Rows.Columns()
+	assert.Error(t, err)
+
+	r, err = g.Query(NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	var errs []error
+
+	for row, err := range r.All() {
+		if err != nil {
+			assert.Nil(t, row)
+			errs = append(errs, err)
+		}
+	}
+
+	assert.Equal(t, []error{fail}, errs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
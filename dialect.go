@@ -0,0 +1,276 @@
+package gdo
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect controls the SQL gdo generates for a particular database: the
+// placeholders named parameters are rewritten to, how identifiers are quoted
+// and how string literals are escaped.
+type Dialect interface {
+	// Name returns the name of the database, e.g. "mysql"
+	Name() string
+	// Placeholder returns the placeholder for the nth argument, starting at 1
+	Placeholder(n int) string
+	// QuoteIdent quotes a table, column or savepoint name
+	QuoteIdent(ident string) string
+	// QuoteString returns s as a quoted and escaped string literal
+	QuoteString(s string) string
+}
+
+// SavepointDialect is implemented by a Dialect whose savepoint statements
+// differ from the standard SAVEPOINT, ROLLBACK TO SAVEPOINT and RELEASE
+// SAVEPOINT.  Each method returns the statement for an already quoted name,
+// or "" when the database has no such statement.
+type SavepointDialect interface {
+	Savepoint(name string) string
+	RollbackToSavepoint(name string) string
+	ReleaseSavepoint(name string) string
+}
+
+// LiteralDialect is implemented by a Dialect whose literals for booleans,
+// binary strings or times differ from the ones Interpolate writes by default:
+// TRUE and FALSE, X'0a1b' and '2006-01-02 15:04:05.999999999-07:00'.
+type LiteralDialect interface {
+	BoolLiteral(b bool) string
+	BytesLiteral(b []byte) string
+	TimeLiteral(t time.Time) string
+}
+
+// SyntaxDialect is implemented by a Dialect whose lexical syntax departs from
+// standard SQL in ways that matter for finding named parameters.  A Dialect
+// that does not implement it is read as standard SQL.
+type SyntaxDialect interface {
+	// BackslashEscapes reports whether a backslash escapes the next
+	// character of a quoted string
+	BackslashEscapes() bool
+	// HashComments reports whether # starts a line comment
+	HashComments() bool
+}
+
+var (
+	// MySQL uses ? placeholders and `backquoted` identifiers.  It is the
+	// default dialect.
+	MySQL Dialect = mysqlDialect{}
+	// Postgres uses $1 placeholders and "double quoted" identifiers
+	Postgres Dialect = postgresDialect{}
+	// SQLite uses ? placeholders and "double quoted" identifiers
+	SQLite Dialect = sqliteDialect{}
+	// SQLServer uses @p1 placeholders and [bracketed] identifiers
+	SQLServer Dialect = sqlServerDialect{}
+	// Oracle uses :1 placeholders and "double quoted" identifiers
+	Oracle Dialect = oracleDialect{}
+)
+
+type mysqlDialect struct{}
+
+var mysqlStringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `''`)
+
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (mysqlDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (mysqlDialect) QuoteIdent(ident string) string {
+	return quoteWith(ident, '`', '`')
+}
+
+func (mysqlDialect) QuoteString(s string) string {
+	return "'" + mysqlStringEscaper.Replace(s) + "'"
+}
+
+func (mysqlDialect) BoolLiteral(b bool) string {
+	return standardLiterals{}.BoolLiteral(b)
+}
+
+func (mysqlDialect) BytesLiteral(b []byte) string {
+	return standardLiterals{}.BytesLiteral(b)
+}
+
+func (mysqlDialect) BackslashEscapes() bool {
+	return true
+}
+
+func (mysqlDialect) HashComments() bool {
+	return true
+}
+
+// TimeLiteral drops the time zone, which DATETIME columns do not hold
+func (d mysqlDialect) TimeLiteral(t time.Time) string {
+	return d.QuoteString(t.Format("2006-01-02 15:04:05.999999"))
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (postgresDialect) QuoteIdent(ident string) string {
+	return quoteWith(ident, '"', '"')
+}
+
+func (postgresDialect) QuoteString(s string) string {
+	return quoteWith(s, '\'', '\'')
+}
+
+func (postgresDialect) BoolLiteral(b bool) string {
+	return standardLiterals{}.BoolLiteral(b)
+}
+
+func (postgresDialect) BytesLiteral(b []byte) string {
+	return `'\x` + hex.EncodeToString(b) + "'::bytea"
+}
+
+func (postgresDialect) TimeLiteral(t time.Time) string {
+	return standardLiterals{}.TimeLiteral(t)
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (sqliteDialect) QuoteIdent(ident string) string {
+	return quoteWith(ident, '"', '"')
+}
+
+func (sqliteDialect) QuoteString(s string) string {
+	return quoteWith(s, '\'', '\'')
+}
+
+type sqlServerDialect struct{}
+
+func (sqlServerDialect) Name() string {
+	return "sqlserver"
+}
+
+func (sqlServerDialect) Placeholder(n int) string {
+	return "@p" + strconv.Itoa(n)
+}
+
+func (sqlServerDialect) QuoteIdent(ident string) string {
+	return quoteWith(ident, '[', ']')
+}
+
+func (sqlServerDialect) QuoteString(s string) string {
+	return quoteWith(s, '\'', '\'')
+}
+
+// BoolLiteral returns 1 or 0 as SQL Server has no boolean literals
+func (sqlServerDialect) BoolLiteral(b bool) string {
+	if b {
+		return "1"
+	}
+
+	return "0"
+}
+
+func (sqlServerDialect) BytesLiteral(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func (d sqlServerDialect) TimeLiteral(t time.Time) string {
+	return d.QuoteString(t.Format("2006-01-02T15:04:05.9999999"))
+}
+
+func (sqlServerDialect) Savepoint(name string) string {
+	return "SAVE TRANSACTION " + name
+}
+
+func (sqlServerDialect) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TRANSACTION " + name
+}
+
+// ReleaseSavepoint returns "" as SQL Server savepoints last until the end of
+// the transaction
+func (sqlServerDialect) ReleaseSavepoint(name string) string {
+	return ""
+}
+
+type oracleDialect struct{}
+
+func (oracleDialect) Name() string {
+	return "oracle"
+}
+
+func (oracleDialect) Placeholder(n int) string {
+	return ":" + strconv.Itoa(n)
+}
+
+func (oracleDialect) QuoteIdent(ident string) string {
+	return quoteWith(ident, '"', '"')
+}
+
+func (oracleDialect) QuoteString(s string) string {
+	return quoteWith(s, '\'', '\'')
+}
+
+// BoolLiteral returns 1 or 0 as Oracle SQL has no boolean literals
+func (oracleDialect) BoolLiteral(b bool) string {
+	return sqlServerDialect{}.BoolLiteral(b)
+}
+
+func (oracleDialect) BytesLiteral(b []byte) string {
+	return "HEXTORAW('" + hex.EncodeToString(b) + "')"
+}
+
+func (oracleDialect) TimeLiteral(t time.Time) string {
+	return "TIMESTAMP '" + t.Format("2006-01-02 15:04:05.999999999") + "'"
+}
+
+func (oracleDialect) Savepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (oracleDialect) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+// ReleaseSavepoint returns "" as Oracle has no RELEASE SAVEPOINT
+func (oracleDialect) ReleaseSavepoint(name string) string {
+	return ""
+}
+
+// quoteWith wraps s in open and close, doubling any close inside s
+func quoteWith(s string, open, close byte) string {
+	return string(open) + strings.Replace(s, string(close), string(close)+string(close), -1) + string(close)
+}
+
+// syntaxOf returns the lexical syntax of queries written for d
+func syntaxOf(d Dialect) syntax {
+	sd, ok := d.(SyntaxDialect)
+
+	if !ok {
+		return syntax{}
+	}
+
+	return syntax{backslashEscapes: sd.BackslashEscapes(), hashComments: sd.HashComments()}
+}
+
+// placeholderPrefix returns the prefix of a numbered placeholder style such
+// as $ or @p, or "" when the dialect uses ? placeholders
+func placeholderPrefix(d Dialect) string {
+	p := d.Placeholder(1)
+
+	if p == "?" {
+		return ""
+	}
+
+	return strings.TrimSuffix(p, "1")
+}
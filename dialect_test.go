@@ -0,0 +1,178 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestDialects(t *testing.T) {
+	cases := []map[string]interface{}{
+		map[string]interface{}{
+			"dialect":     MySQL,
+			"placeholder": "?",
+			"ident":       "`a``b`",
+			"string":      `'it''s \\'`,
+		},
+		map[string]interface{}{
+			"dialect":     Postgres,
+			"placeholder": "$2",
+			"ident":       "\"a`b\"",
+			"string":      `'it''s \'`,
+		},
+		map[string]interface{}{
+			"dialect":     SQLite,
+			"placeholder": "?",
+			"ident":       "\"a`b\"",
+			"string":      `'it''s \'`,
+		},
+		map[string]interface{}{
+			"dialect":     SQLServer,
+			"placeholder": "@p2",
+			"ident":       "[a`b]",
+			"string":      `'it''s \'`,
+		},
+		map[string]interface{}{
+			"dialect":     Oracle,
+			"placeholder": ":2",
+			"ident":       "\"a`b\"",
+			"string":      `'it''s \'`,
+		},
+	}
+
+	for _, c := range cases {
+		d := c["dialect"].(Dialect)
+
+		assert.Equal(t, c["placeholder"], d.Placeholder(2), d.Name())
+		assert.Equal(t, c["ident"], d.QuoteIdent("a`b"), d.Name())
+		assert.Equal(t, c["string"], d.QuoteString(`it's \`), d.Name())
+	}
+
+	assert.Equal(t, `"a""b"`, Postgres.QuoteIdent(`a"b`))
+	assert.Equal(t, "[a]]b]", SQLServer.QuoteIdent("a]b"))
+}
+
+func TestProcessStatementDialect(t *testing.T) {
+	cases := []map[string]interface{}{
+		map[string]interface{}{
+			"dialect":      MySQL,
+			"query":        "SELECT * FROM Foo WHERE id = ? AND bar = ? AND baz = ?",
+			"lastExecuted": "SELECT * FROM Foo WHERE id = 1 AND bar = 'it''s' AND baz = 1",
+		},
+		map[string]interface{}{
+			"dialect":      Postgres,
+			"query":        "SELECT * FROM Foo WHERE id = $1 AND bar = $2 AND baz = $3",
+			"lastExecuted": "SELECT * FROM Foo WHERE id = 1 AND bar = 'it''s' AND baz = 1",
+		},
+		map[string]interface{}{
+			"dialect":      SQLServer,
+			"query":        "SELECT * FROM Foo WHERE id = @p1 AND bar = @p2 AND baz = @p3",
+			"lastExecuted": "SELECT * FROM Foo WHERE id = 1 AND bar = 'it''s' AND baz = 1",
+		},
+		map[string]interface{}{
+			"dialect":      Oracle,
+			"query":        "SELECT * FROM Foo WHERE id = :1 AND bar = :2 AND baz = :3",
+			"lastExecuted": "SELECT * FROM Foo WHERE id = 1 AND bar = 'it''s' AND baz = 1",
+		},
+	}
+
+	for _, c := range cases {
+		d := c["dialect"].(Dialect)
+
+		stmt := NewStatement("SELECT * FROM Foo WHERE id = :a: AND bar = :b: AND baz = :a:")
+		stmt.BindNamedArg(sql.Named("a", 1))
+		stmt.BindNamedArg(sql.Named("b", "it's"))
+
+		cfg := newConfig([]Option{WithDialect(d)})
+
+		newStmt, err := processStatment(cfg, stmt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, c["query"], newStmt.query, d.Name())
+		assert.Equal(t, []interface{}{1, "it's", 1}, newStmt.args, d.Name())
+		assert.Equal(t, c["lastExecuted"], newStmt.lastExecutedQuery(cfg), d.Name())
+	}
+}
+
+func TestWithDialect(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectPrepare(`SELECT \* FROM Foo WHERE id = \$1 AND bar = \$2`)
+
+	g := New(db, WithDialect(Postgres))
+
+	assert.Equal(t, Postgres, g.Dialect())
+	assert.Equal(t, MySQL, New(db).Dialect())
+
+	ps, err := g.PrepareContext(context.Background(), "SELECT * FROM Foo WHERE id = :a: AND bar = :b:")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM Foo WHERE id = $1 AND bar = $2", ps.query)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDialectSyntax(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	query := `SELECT * FROM Foo WHERE p = 'C:\' AND id = :id:`
+
+	mock.ExpectExec(`SELECT \* FROM Foo WHERE p = 'C:\\' AND id = \$1`).WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(`SELECT \* FROM Foo WHERE p = 'C:\\' AND id = \$1`)
+
+	g := New(db, WithDialect(Postgres))
+
+	stmt := NewStatement(query)
+	stmt.BindNamedArg(sql.Named("id", 7))
+
+	_, err := g.ExecContext(context.Background(), stmt)
+
+	assert.NoError(t, err)
+
+	ps, err := g.PrepareContext(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id"}, ps.tmpl.names)
+	assert.Equal(t, "SELECT 'C:\\', 7", Interpolate(Postgres, "SELECT 'C:\\', $1", []interface{}{7}))
+	assert.Equal(t, "SELECT * FROM Foo WHERE p = ? AND id = ?", normalize(query, syntaxOf(Postgres)))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDialectSyntaxBind(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	query := `SELECT * FROM Foo WHERE p = 'C:\' AND id = :id: AND name = :name:`
+
+	mock.ExpectQuery(`SELECT \* FROM Foo WHERE p = 'C:\\' AND id = \$1 AND name = \$2`).WithArgs(7, "foo").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT \* FROM Foo WHERE p = 'C:\\' AND id = \$1 AND name = \$2`).WithArgs(8, "foo").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	g := New(db, WithDialect(Postgres))
+
+	stmt := NewStatement(query)
+
+	// read as MySQL the query has no parameters
+	assert.NoError(t, stmt.BindStruct(struct{ ID int }{ID: 7}))
+	stmt.BindNamedArg(sql.Named("name", "foo"))
+
+	r, err := g.Query(stmt)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+
+	assert.NoError(t, stmt.BindMap(map[string]interface{}{"id": 8}))
+
+	r, err = g.Query(stmt)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+
+	// the query is compiled for Postgres once per statement
+	assert.Same(t, stmt.templateFor(Postgres), stmt.templateFor(Postgres))
+	assert.Same(t, stmt.tmpl, stmt.templateFor(MySQL))
+	assert.Equal(t, []string{"id", "name"}, stmt.templateFor(Postgres).names)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
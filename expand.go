@@ -0,0 +1,105 @@
+package gdo
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var ErrEmptySlice = errors.New("gdo: cannot expand an empty slice")
+
+// EmptySlice selects what a named parameter bound to an empty slice expands to
+type EmptySlice int
+
+const (
+	// EmptySliceError fails the statement with ErrEmptySlice.  It is the default.
+	EmptySliceError EmptySlice = iota
+	// EmptySliceNull expands the parameter to a single NULL, so that
+	// id IN (:ids:) matches nothing
+	EmptySliceNull
+	// EmptySliceFalse rewrites a whole "expr IN (:ids:)" predicate to 1=0, and
+	// "expr NOT IN (:ids:)" to 1=1.  A parameter used anywhere else fails
+	// with ErrEmptySlice.
+	EmptySliceFalse
+)
+
+const identPattern = "(?:[\\w$]+|`(?:[^`]|``)*`|\"(?:[^\"]|\"\")*\"|\\[[^\\]]*\\])"
+
+// inPredicate matches the "expr [NOT] IN (" in front of a parameter
+var inPredicate = regexp.MustCompile(`(?i)` + identPattern + `(?:\s*\.\s*` + identPattern + `)*\s+(NOT\s+)?IN\s*\(\s*$`)
+
+// expandable reports the elements of v when it is a slice or array that should
+// be spread over one placeholder per element.  []byte and driver.Valuer
+// values are passed to the driver as they are.
+func expandable(v interface{}) ([]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	if s, ok := v.(sensitive); ok {
+		values, ok := expandable(s.v)
+
+		for i := range values {
+			values[i] = sensitive{values[i]}
+		}
+
+		return values, ok
+	}
+
+	if _, ok := v.(driver.Valuer); ok {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	if rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+
+	values := make([]interface{}, rv.Len())
+
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+
+	return values, true
+}
+
+func hasExpandable(namedArgs []sql.NamedArg) bool {
+	for _, arg := range namedArgs {
+		if _, ok := expandable(arg.Value); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rewriteEmptyIn finds the "expr [NOT] IN (" at the end of query and the ")"
+// at the start of rest that surround an empty slice parameter.  It returns
+// where the predicate starts in query, the constant predicate replacing it
+// and what is left of rest.  ok is false when the parameter is not the only
+// member of an IN list.
+func rewriteEmptyIn(query, rest string) (start int, predicate, newRest string, ok bool) {
+	loc := inPredicate.FindStringSubmatchIndex(query)
+
+	trimmed := strings.TrimLeft(rest, " \t\r\n")
+
+	if loc == nil || !strings.HasPrefix(trimmed, ")") {
+		return 0, "", rest, false
+	}
+
+	predicate = "1=0"
+	if loc[2] >= 0 {
+		predicate = "1=1"
+	}
+
+	return loc[0], predicate, trimmed[1:], true
+}
@@ -0,0 +1,120 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestProcessStatementSlices(t *testing.T) {
+	cases := []map[string]interface{}{
+		map[string]interface{}{
+			"options":       []Option{},
+			"query":         "SELECT * FROM Foo WHERE id IN (:ids:) AND bar = :b:",
+			"ids":           []int{1, 2, 3},
+			"expectedQuery": "SELECT * FROM Foo WHERE id IN (?, ?, ?) AND bar = ?",
+			"args":          []interface{}{1, 2, 3, "b"},
+		},
+		map[string]interface{}{
+			"options":       []Option{WithDialect(Postgres)},
+			"query":         "SELECT * FROM Foo WHERE id IN (:ids:) AND bar = :b:",
+			"ids":           [2]string{"x", "y"},
+			"expectedQuery": "SELECT * FROM Foo WHERE id IN ($1, $2) AND bar = $3",
+			"args":          []interface{}{"x", "y", "b"},
+		},
+		map[string]interface{}{
+			"options":       []Option{},
+			"query":         "SELECT * FROM Foo WHERE data = :ids: AND bar = :b:",
+			"ids":           []byte("raw"),
+			"expectedQuery": "SELECT * FROM Foo WHERE data = ? AND bar = ?",
+			"args":          []interface{}{[]byte("raw"), "b"},
+		},
+		map[string]interface{}{
+			"options": []Option{},
+			"query":   "SELECT * FROM Foo WHERE id IN (:ids:) AND bar = :b:",
+			"ids":     []int{},
+			"error":   ErrEmptySlice,
+		},
+		map[string]interface{}{
+			"options":       []Option{WithEmptySlice(EmptySliceNull)},
+			"query":         "SELECT * FROM Foo WHERE id IN (:ids:) AND bar = :b:",
+			"ids":           []int{},
+			"expectedQuery": "SELECT * FROM Foo WHERE id IN (NULL) AND bar = ?",
+			"args":          []interface{}{"b"},
+		},
+		map[string]interface{}{
+			"options":       []Option{WithEmptySlice(EmptySliceFalse)},
+			"query":         "SELECT * FROM Foo WHERE f.id IN ( :ids: ) AND bar = :b:",
+			"ids":           []int{},
+			"expectedQuery": "SELECT * FROM Foo WHERE 1=0 AND bar = ?",
+			"args":          []interface{}{"b"},
+		},
+		map[string]interface{}{
+			"options":       []Option{WithEmptySlice(EmptySliceFalse)},
+			"query":         "SELECT * FROM Foo WHERE `f`.`id` not in (:ids:) AND bar = :b:",
+			"ids":           []int{},
+			"expectedQuery": "SELECT * FROM Foo WHERE 1=1 AND bar = ?",
+			"args":          []interface{}{"b"},
+		},
+		map[string]interface{}{
+			"options": []Option{WithEmptySlice(EmptySliceFalse)},
+			"query":   "SELECT * FROM Foo WHERE id IN (0, :ids:) AND bar = :b:",
+			"ids":     []int{},
+			"error":   ErrEmptySlice,
+		},
+	}
+
+	for _, c := range cases {
+		stmt := NewStatement(c["query"].(string))
+		stmt.BindNamedArg(sql.Named("ids", c["ids"]))
+		stmt.BindNamedArg(sql.Named("b", "b"))
+
+		newStmt, err := processStatment(newConfig(c["options"].([]Option)), stmt)
+
+		if c["error"] != nil {
+			assert.Equal(t, c["error"], err, c["query"].(string))
+			assert.Nil(t, newStmt)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, c["expectedQuery"], newStmt.query)
+			assert.Equal(t, c["args"], newStmt.args)
+		}
+	}
+}
+
+func TestPreparedStatementSlices(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta("SELECT * FROM Foo WHERE id IN (?) AND bar = ?"))
+
+	expanded := mock.ExpectPrepare(regexp.QuoteMeta("SELECT * FROM Foo WHERE id IN (?, ?, ?) AND bar = ?"))
+	expanded.ExpectQuery().WithArgs(1, 2, 3, "b").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	expanded.ExpectQuery().WithArgs(4, 5, 6, "c").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	g := New(db)
+
+	ps, err := g.PrepareContext(context.Background(), "SELECT * FROM Foo WHERE id IN (:ids:) AND bar = :b:")
+	assert.NoError(t, err)
+
+	ps.BindNamedArgs([]sql.NamedArg{sql.Named("ids", []int{1, 2, 3}), sql.Named("b", "b")})
+
+	r, err := ps.Query()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM Foo WHERE id IN (1, 2, 3) AND bar = 'b'", r.LastExecutedQuery())
+	r.FetchRows()
+
+	// the same arity reuses the statement prepared above
+	ps.BindNamedArgs([]sql.NamedArg{sql.Named("ids", []int{4, 5, 6}), sql.Named("b", "c")})
+
+	r, err = ps.Query()
+	assert.NoError(t, err)
+	r.FetchRows()
+
+	assert.Len(t, ps.arities.stmts, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
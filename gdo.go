@@ -3,21 +3,36 @@ package gdo
 import (
 	"context"
 	"database/sql"
-	"strings"
-	"unicode"
+	"time"
 )
 
-const delim = ":"
-
 type queryCtxFunc func(context.Context, string, ...interface{}) (*sql.Rows, error)
 type execCtxFunc func(context.Context, string, ...interface{}) (sql.Result, error)
 
 type GDO struct {
 	*sql.DB
+	cfg *config
+}
+
+func New(db *sql.DB, opts ...Option) *GDO {
+	cfg := newConfig(opts)
+
+	if cfg.metrics != nil {
+		cfg.metrics.observeDB(db)
+	}
+
+	return &GDO{DB: db, cfg: cfg}
+}
+
+// Dialect returns the Dialect statements are rewritten for
+func (g GDO) Dialect() Dialect {
+	return g.cfg.getDialect()
 }
 
-func New(db *sql.DB) *GDO {
-	return &GDO{db}
+// Location returns the time zone set with WithLocation, which a Converter
+// can be given to read the times of a Row in it
+func (g GDO) Location() *time.Location {
+	return g.cfg.getLocation()
 }
 
 func (g GDO) BeginTx() (Transaction, error) {
@@ -25,9 +40,49 @@ func (g GDO) BeginTx() (Transaction, error) {
 }
 
 func (g GDO) BeginTxContext(ctx context.Context, opts *sql.TxOptions) (Transaction, error) {
-	tx, err := g.DB.BeginTx(ctx, opts)
+	return beginTx(ctx, g.DB, g.cfg, opts)
+}
+
+// WithTx runs fn in a transaction, committing it when fn returns nil and
+// rolling it back when fn returns an error or panics, in which case the panic
+// is resumed after the rollback.  Failed attempts are run again in a new
+// transaction as allowed by the RetryPolicy of g.
+func (g GDO) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(tx Transaction) error) error {
+	policy := g.cfg.getRetryPolicy()
+
+	for attempt := 1; ; attempt++ {
+		err := g.runTx(ctx, opts, fn)
+
+		if err == nil || attempt >= policy.MaxAttempts || !policy.retryable(err) {
+			return err
+		}
+
+		if werr := policy.wait(ctx, attempt+1); werr != nil {
+			return err
+		}
+	}
+}
+
+func (g GDO) runTx(ctx context.Context, opts *sql.TxOptions, fn func(tx Transaction) error) error {
+	tx, err := g.BeginTxContext(ctx, opts)
+
+	if err != nil {
+		return err
+	}
 
-	return Transaction{tx}, err
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (g GDO) Prepare(query string) (*PreparedStatement, error) {
@@ -35,6 +90,10 @@ func (g GDO) Prepare(query string) (*PreparedStatement, error) {
 }
 
 func (g GDO) PrepareContext(ctx context.Context, query string) (*PreparedStatement, error) {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.PrepareContext(ctx, query)
+	}
+
 	return g.prepareContext(ctx, query)
 }
 
@@ -43,7 +102,11 @@ func (g GDO) Exec(s *Statement) (ExecResult, error) {
 }
 
 func (g GDO) ExecContext(ctx context.Context, s *Statement) (ExecResult, error) {
-	return doExecCtx(g.DB.ExecContext, ctx, s)
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.ExecContext(ctx, s)
+	}
+
+	return doExecCtx(g.DB.ExecContext, ctx, g.cfg, s)
 }
 
 func (g GDO) Query(s *Statement) (QueryResult, error) {
@@ -51,7 +114,11 @@ func (g GDO) Query(s *Statement) (QueryResult, error) {
 }
 
 func (g GDO) QueryContext(ctx context.Context, s *Statement) (QueryResult, error) {
-	return doQueryCtx(g.DB.QueryContext, ctx, s)
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.QueryContext(ctx, s)
+	}
+
+	return doQueryCtx(g.DB.QueryContext, ctx, g.cfg, s)
 }
 
 func (g GDO) QueryRow(s *Statement) QueryRowResult {
@@ -59,28 +126,39 @@ func (g GDO) QueryRow(s *Statement) QueryRowResult {
 }
 
 func (g GDO) QueryRowContext(ctx context.Context, s *Statement) QueryRowResult {
-	return doQueryRowCtx(g.DB.QueryContext, ctx, s)
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.QueryRowContext(ctx, s)
+	}
+
+	return doQueryRowCtx(g.DB.QueryContext, ctx, g.cfg, s)
 }
 
 func (g GDO) prepareContext(ctx context.Context, query string) (*PreparedStatement, error) {
+	return prepareContext(ctx, g.DB, g.cfg, query)
+}
+
+func prepareContext(ctx context.Context, p preparer, cfg *config, query string) (*PreparedStatement, error) {
 	replacedSQL := query
 	var qna queryNamedArgs
 
-	isParameterized := checkIsParameterized(replacedSQL)
+	tmpl := compileFor(query, cfg.getDialect())
+	isParameterized := tmpl.isParameterized()
 
 	if isParameterized {
-		var toReplace []string
+		qna = tmpl.queryNamedArgs()
+		replacedSQL = tmpl.placeholders(cfg.getDialect())
+	}
 
-		qna = getQueryParameters(query)
+	var ps *sql.Stmt
 
-		for key := range qna.dict {
-			toReplace = append(toReplace, delim+key+delim, "?")
-		}
+	e := &Execution{Op: OpPrepare, Statement: &Statement{query: replacedSQL, isParameterized: isParameterized, tmpl: tmpl}}
 
-		replacedSQL = strings.NewReplacer(toReplace...).Replace(query)
-	}
+	err := intercept(ctx, cfg, e, func(ctx context.Context) error {
+		var perr error
+		ps, perr = p.PrepareContext(ctx, e.Statement.query)
 
-	ps, err := g.DB.PrepareContext(ctx, replacedSQL)
+		return perr
+	})
 
 	if err != nil {
 		return &PreparedStatement{}, err
@@ -93,24 +171,35 @@ func (g GDO) prepareContext(ctx context.Context, query string) (*PreparedStateme
 			namedArgs:       make([]sql.NamedArg, 0),
 			args:            make([]interface{}, 0),
 			isParameterized: isParameterized,
+			tmpl:            tmpl,
 		},
 		queryNamedArgs: qna,
+		cfg:            cfg,
+		preparer:       p,
+		arities:        &stmtCache{},
 	}, nil
 }
 
-func doQueryCtx(fn queryCtxFunc, ctx context.Context, s *Statement) (QueryResult, error) {
+func doQueryCtx(fn queryCtxFunc, ctx context.Context, cfg *config, s *Statement) (QueryResult, error) {
 	var rows *sql.Rows
 	var err error
 
-	if s.isParameterized && len(s.namedArgs) > 0 {
-		s, err = processStatment(s)
+	if s.hasNamedArgs() && s.templateFor(cfg.getDialect()).isParameterized() {
+		s, err = processStatment(cfg, s)
 
 		if err != nil {
 			return QueryResult{}, err
 		}
 	}
 
-	rows, err = fn(ctx, s.query, s.args...)
+	e := &Execution{Op: OpQuery, Statement: s}
+
+	err = intercept(ctx, cfg, e, func(ctx context.Context) error {
+		var qerr error
+		rows, qerr = fn(ctx, e.Statement.query, unwrapArgs(e.Statement.args)...)
+
+		return qerr
+	})
 
 	if err != nil {
 		return QueryResult{}, err
@@ -124,14 +213,16 @@ func doQueryCtx(fn queryCtxFunc, ctx context.Context, s *Statement) (QueryResult
 
 	return QueryResult{
 		GDOResult: GDOResult{
-			executedStmt: s,
+			executedStmt: e.Statement,
+			cfg:          cfg,
 		},
 		Rows: rows, Cols: cols,
+		cursor: newCursor(ctx, cfg, rows, cols),
 	}, nil
 }
 
-func doQueryRowCtx(fn queryCtxFunc, ctx context.Context, s *Statement) QueryRowResult {
-	rs, err := doQueryCtx(fn, ctx, s)
+func doQueryRowCtx(fn queryCtxFunc, ctx context.Context, cfg *config, s *Statement) QueryRowResult {
+	rs, err := doQueryCtx(fn, ctx, cfg, s)
 
 	if err != nil {
 		return QueryRowResult{err: err}
@@ -140,19 +231,28 @@ func doQueryRowCtx(fn queryCtxFunc, ctx context.Context, s *Statement) QueryRowR
 	return QueryRowResult{QueryResult: rs, err: nil}
 }
 
-func doExecCtx(fn execCtxFunc, ctx context.Context, s *Statement) (ExecResult, error) {
+func doExecCtx(fn execCtxFunc, ctx context.Context, cfg *config, s *Statement) (ExecResult, error) {
 	var result sql.Result
 	var err error
 
-	if s.isParameterized && len(s.namedArgs) > 0 {
-		s, err = processStatment(s)
+	if s.hasNamedArgs() && s.templateFor(cfg.getDialect()).isParameterized() {
+		s, err = processStatment(cfg, s)
 
 		if err != nil {
 			return ExecResult{}, err
 		}
 	}
 
-	result, err = fn(ctx, s.query, s.args...)
+	e := &Execution{Op: OpExec, Statement: s}
+
+	err = intercept(ctx, cfg, e, func(ctx context.Context) error {
+		var eerr error
+		result, eerr = fn(ctx, e.Statement.query, unwrapArgs(e.Statement.args)...)
+
+		setRowsAffected(e, result)
+
+		return eerr
+	})
 
 	if err != nil {
 		return ExecResult{}, err
@@ -160,54 +260,13 @@ func doExecCtx(fn execCtxFunc, ctx context.Context, s *Statement) (ExecResult, e
 
 	return ExecResult{
 		GDOResult: GDOResult{
-			executedStmt: s,
+			executedStmt: e.Statement,
+			cfg:          cfg,
 		},
 		Result: result,
 	}, nil
 }
 
 func checkIsParameterized(query string) bool {
-	var paramCount []bool
-
-	for i, r := range query {
-		if r == ':' {
-			for j := i + 1; j < len(query); j++ {
-				if query[j] == ':' {
-					paramCount = append(paramCount, true)
-					break
-				} else if unicode.IsSpace(rune(query[j])) {
-					break
-				}
-			}
-		}
-	}
-
-	return len(paramCount) > 0
-}
-
-func getQueryParameters(query string) queryNamedArgs {
-	qnp := queryNamedArgs{
-		dict: make(map[string][]int),
-	}
-
-	var order int
-	for i, r := range query {
-		if r == ':' {
-			for j, start := i+1, i+1; j < len(query); j++ {
-				if query[j] == ':' {
-					qnp.dict[query[start:j]] = append(qnp.dict[query[start:j]], order)
-
-					order++
-					break
-				} else if unicode.IsSpace(rune(query[j])) {
-					break
-				}
-			}
-		}
-	}
-
-	// keeping track of the order also keeps the total at the end
-	qnp.total = order
-
-	return qnp
+	return compile(query).isParameterized()
 }
@@ -0,0 +1,98 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Operation is the kind of database call an Execution makes
+type Operation string
+
+const (
+	// OpExec is a call that returns an ExecResult
+	OpExec Operation = "exec"
+	// OpQuery is a call that returns a QueryResult or QueryRowResult
+	OpQuery Operation = "query"
+	// OpPrepare is the preparation of a PreparedStatement, whose Statement
+	// has no arguments
+	OpPrepare Operation = "prepare"
+)
+
+// Execution describes one statement sent to the database.  Op, Statement and
+// Prepared are set before the interceptors are called; Err, Duration and
+// RowsAffected once next returns.
+type Execution struct {
+	Op Operation
+	// Statement is the statement as it is sent: named parameters already
+	// rewritten to placeholders and their values in Args.  An interceptor may
+	// replace it before calling next, though for a prepared statement only
+	// its args are used.
+	Statement *Statement
+	// Prepared is true when the statement runs on a PreparedStatement
+	Prepared bool
+	// TxID is the ID of the Transaction the statement runs in, or 0
+	TxID uint64
+
+	Err      error
+	Duration time.Duration
+	// RowsAffected is set for OpExec when the driver reports it
+	RowsAffected int64
+
+	cfg *config
+}
+
+// LastExecutedQuery returns the statement with its arguments interpolated, as
+// GDOResult.LastExecutedQuery does, sensitive values redacted
+func (e *Execution) LastExecutedQuery() string {
+	return e.Statement.lastExecutedQuery(e.cfg)
+}
+
+// Interceptor wraps the execution of a statement.  It must call next to run
+// the statement, optionally with a derived context, and returns the error
+// the caller sees, which is normally the one next returned.
+type Interceptor func(ctx context.Context, e *Execution, next func(ctx context.Context) error) error
+
+// WithInterceptors adds interceptors around every statement executed through
+// the GDO, including on its transactions, connections and prepared
+// statements.  The first interceptor is the outermost.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *config) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// intercept runs exec for e through the interceptors of cfg
+func intercept(ctx context.Context, cfg *config, e *Execution, exec func(ctx context.Context) error) error {
+	chain := cfg.getInterceptors()
+	e.cfg = cfg
+	e.TxID = cfg.getTxID()
+
+	var call func(i int, ctx context.Context) error
+
+	call = func(i int, ctx context.Context) error {
+		if i < len(chain) {
+			return chain[i](ctx, e, func(ctx context.Context) error {
+				return call(i+1, ctx)
+			})
+		}
+
+		start := time.Now()
+		e.Err = exec(ctx)
+		e.Duration = time.Since(start)
+
+		return e.Err
+	}
+
+	return call(0, ctx)
+}
+
+func setRowsAffected(e *Execution, result sql.Result) {
+	if result == nil {
+		return
+	}
+
+	if n, err := result.RowsAffected(); err == nil {
+		e.RowsAffected = n
+	}
+}
@@ -0,0 +1,131 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type ctxKey string
+
+func TestInterceptors(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE Foo SET a = ? WHERE id = ?")).WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT a FROM Foo")).WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(int64(1)))
+	mock.ExpectPrepare(regexp.QuoteMeta("DELETE FROM Foo WHERE id = ?")).
+		ExpectExec().WithArgs(4).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var calls []string
+	var seen []Execution
+
+	outer := func(ctx context.Context, e *Execution, next func(context.Context) error) error {
+		calls = append(calls, "outer")
+
+		err := next(context.WithValue(ctx, ctxKey("k"), "v"))
+		seen = append(seen, *e)
+
+		return err
+	}
+
+	inner := func(ctx context.Context, e *Execution, next func(context.Context) error) error {
+		calls = append(calls, "inner")
+		assert.Equal(t, "v", ctx.Value(ctxKey("k")))
+
+		return next(ctx)
+	}
+
+	g := New(db, WithInterceptors(outer), WithInterceptors(inner))
+
+	s := NewStatement("UPDATE Foo SET a = :a: WHERE id = :id:")
+	s.BindNamedArgs([]sql.NamedArg{sql.Named("a", 1), sql.Named("id", 2)})
+
+	_, err := g.Exec(s)
+	assert.NoError(t, err)
+
+	tx, err := g.BeginTx()
+	assert.NoError(t, err)
+
+	_, err = tx.Query(NewStatement("SELECT a FROM Foo"))
+	assert.NoError(t, err)
+
+	ps, err := tx.Prepare("DELETE FROM Foo WHERE id = :id:")
+	assert.NoError(t, err)
+
+	ps.BindNamedArg(sql.Named("id", 4))
+
+	_, err = ps.Exec()
+	assert.NoError(t, err)
+
+	assert.NoError(t, tx.Commit())
+
+	assert.Equal(t, []string{"outer", "inner", "outer", "inner", "outer", "inner", "outer", "inner"}, calls)
+	assert.Len(t, seen, 4)
+
+	assert.Equal(t, OpExec, seen[0].Op)
+	assert.Equal(t, "UPDATE Foo SET a = ? WHERE id = ?", seen[0].Statement.Query())
+	assert.Equal(t, []interface{}{1, 2}, seen[0].Statement.Args())
+	assert.Equal(t, []sql.NamedArg{sql.Named("a", 1), sql.Named("id", 2)}, seen[0].Statement.NamedArgs())
+	assert.Equal(t, int64(3), seen[0].RowsAffected)
+	assert.False(t, seen[0].Prepared)
+
+	assert.Equal(t, OpQuery, seen[1].Op)
+	assert.Equal(t, "SELECT a FROM Foo", seen[1].Statement.Query())
+
+	assert.Equal(t, OpPrepare, seen[2].Op)
+	assert.Equal(t, "DELETE FROM Foo WHERE id = ?", seen[2].Statement.Query())
+
+	assert.Equal(t, OpExec, seen[3].Op)
+	assert.True(t, seen[3].Prepared)
+	assert.Equal(t, []interface{}{4}, seen[3].Statement.Args())
+	assert.Equal(t, int64(1), seen[3].RowsAffected)
+
+	for _, e := range seen {
+		assert.NoError(t, e.Err)
+		assert.True(t, e.Duration > 0)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInterceptorsError(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectQuery("SELECT a FROM Foo").WillReturnError(fail)
+	mock.ExpectExec(regexp.QuoteMeta("/* audited */ UPDATE Foo SET a = 1")).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var got error
+
+	g := New(db, WithInterceptors(func(ctx context.Context, e *Execution, next func(context.Context) error) error {
+		if e.Op == OpExec {
+			e.Statement = NewStatement("/* audited */ " + e.Statement.Query())
+		}
+
+		err := next(ctx)
+		got = e.Err
+
+		return err
+	}))
+
+	_, err := g.Query(NewStatement("SELECT a FROM Foo"))
+	assert.Equal(t, fail, err)
+	assert.Equal(t, fail, got)
+
+	// a replaced statement is the one executed and reported
+	r, err := g.Exec(NewStatement("UPDATE Foo SET a = 1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/* audited */ UPDATE Foo SET a = 1", r.LastExecutedQuery())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,147 @@
+package gdo
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interpolate returns query with each placeholder of d replaced by the
+// literal for its argument in args, written the way d spells it, so that the
+// result can be pasted into a database console.  Placeholders inside string
+// literals, quoted identifiers and comments are left alone, as are those
+// without an argument.
+func Interpolate(d Dialect, query string, args []interface{}) string {
+	if len(args) == 0 {
+		return query
+	}
+
+	var sb strings.Builder
+
+	var next int
+	for _, t := range lexDialect(query, d) {
+		if t.kind != tokenPositional {
+			sb.WriteString(t.text)
+			continue
+		}
+
+		i := next
+		if t.name != "" {
+			i, _ = strconv.Atoi(t.name)
+			i--
+		}
+		next++
+
+		if i < 0 || i >= len(args) {
+			sb.WriteString(t.text)
+			continue
+		}
+
+		sb.WriteString(formatArg(d, args[i]))
+	}
+
+	return sb.String()
+}
+
+// formatArg returns the SQL literal for arg
+func formatArg(d Dialect, arg interface{}) string {
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case sensitive:
+		return redacted
+	case string:
+		return d.QuoteString(v)
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+
+		return literals(d).BytesLiteral(v)
+	case bool:
+		return literals(d).BoolLiteral(v)
+	case time.Time:
+		return literals(d).TimeLiteral(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case driver.Valuer:
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return "NULL"
+		}
+
+		value, err := v.Value()
+
+		if err != nil {
+			return "NULL"
+		}
+
+		return formatArg(d, value)
+	}
+
+	rv := reflect.ValueOf(arg)
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return "NULL"
+		}
+
+		return formatArg(d, rv.Elem().Interface())
+	case reflect.Bool:
+		return literals(d).BoolLiteral(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	case reflect.String:
+		return d.QuoteString(rv.String())
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return formatArg(d, rv.Bytes())
+		}
+	}
+
+	return d.QuoteString(fmt.Sprint(arg))
+}
+
+// standardLiterals writes the literals of a Dialect that is not a
+// LiteralDialect
+type standardLiterals struct{}
+
+func (standardLiterals) BoolLiteral(b bool) string {
+	if b {
+		return "TRUE"
+	}
+
+	return "FALSE"
+}
+
+func (standardLiterals) BytesLiteral(b []byte) string {
+	return "X'" + hex.EncodeToString(b) + "'"
+}
+
+func (standardLiterals) TimeLiteral(t time.Time) string {
+	return "'" + t.Format("2006-01-02 15:04:05.999999999-07:00") + "'"
+}
+
+func literals(d Dialect) LiteralDialect {
+	if ld, ok := d.(LiteralDialect); ok {
+		return ld
+	}
+
+	return standardLiterals{}
+}
@@ -0,0 +1,74 @@
+package gdo
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type status string
+
+func TestInterpolate(t *testing.T) {
+	ts := time.Date(2024, 3, 9, 14, 5, 6, 123000000, time.FixedZone("", -7*3600))
+	one := 1
+	var nilInt *int
+
+	cases := []map[string]interface{}{
+		{
+			"dialect":  MySQL,
+			"query":    "SELECT ?, ?, ?, ?, ?, ?, ?, ?",
+			"args":     []interface{}{true, uint8(7), uint64(1 << 63), int32(-3), []byte{0xde, 0xad}, ts, nil, "it's \\ ?"},
+			"expected": "SELECT TRUE, 7, 9223372036854775808, -3, X'dead', '2024-03-09 14:05:06.123', NULL, 'it''s \\\\ ?'",
+		},
+		{
+			"dialect":  MySQL,
+			"query":    "SELECT '?', ? -- ?\n, `?`, ?",
+			"args":     []interface{}{1, 2},
+			"expected": "SELECT '?', 1 -- ?\n, `?`, 2",
+		},
+		{
+			"dialect":  MySQL,
+			"query":    "SELECT ?, ?, ?, ?, ?, ?, ?",
+			"args":     []interface{}{sql.NullString{}, sql.NullInt64{Int64: 4, Valid: true}, sql.NullBool{Bool: true, Valid: true}, sql.NullTime{Time: ts, Valid: true}, &one, nilInt, status("ok")},
+			"expected": "SELECT NULL, 4, TRUE, '2024-03-09 14:05:06.123', 1, NULL, 'ok'",
+		},
+		{
+			"dialect":  MySQL,
+			"query":    "SELECT ?, ?",
+			"args":     []interface{}{1},
+			"expected": "SELECT 1, ?",
+		},
+		{
+			"dialect":  Postgres,
+			"query":    "SELECT $2, $1, $3, $4, 'a$1'",
+			"args":     []interface{}{false, []byte{1}, ts, "o'k"},
+			"expected": "SELECT '\\x01'::bytea, FALSE, '2024-03-09 14:05:06.123-07:00', 'o''k', 'a$1'",
+		},
+		{
+			"dialect":  SQLite,
+			"query":    "SELECT ?, ?",
+			"args":     []interface{}{true, []byte{0xff}},
+			"expected": "SELECT TRUE, X'ff'",
+		},
+		{
+			"dialect":  SQLServer,
+			"query":    "SELECT @p1, @p2, @p3",
+			"args":     []interface{}{true, []byte{0xab, 0x01}, ts},
+			"expected": "SELECT 1, 0xab01, '2024-03-09T14:05:06.123'",
+		},
+		{
+			"dialect":  Oracle,
+			"query":    "SELECT :1, :2, :3 FROM dual",
+			"args":     []interface{}{false, []byte{0x0a}, ts},
+			"expected": "SELECT 0, HEXTORAW('0a'), TIMESTAMP '2024-03-09 14:05:06.123' FROM dual",
+		},
+	}
+
+	for _, c := range cases {
+		d := c["dialect"].(Dialect)
+
+		assert.Equal(t, c["expected"], Interpolate(d, c["query"].(string), c["args"].([]interface{})), d.Name())
+	}
+}
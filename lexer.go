@@ -0,0 +1,273 @@
+package gdo
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	// tokenSQL is text passed through untouched: SQL code, string literals,
+	// quoted identifiers and comments
+	tokenSQL tokenKind = iota
+	// tokenNamed is a :name: placeholder
+	tokenNamed
+	// tokenPositional is a ? placeholder, or a numbered one such as $1 when
+	// lexing for a Dialect, in which case name holds the number
+	tokenPositional
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	name string
+}
+
+// syntax holds the ways the lexical syntax of a database departs from
+// standard SQL that matter for finding placeholders
+type syntax struct {
+	// backslashEscapes is set when a backslash escapes the next character of
+	// a quoted string.  In standard SQL it is an ordinary character.
+	backslashEscapes bool
+	// hashComments is set when # starts a line comment
+	hashComments bool
+}
+
+// mysqlSyntax is the syntax of MySQL, the default dialect
+var mysqlSyntax = syntax{backslashEscapes: true, hashComments: true}
+
+// lex splits query, written for MySQL, into SQL text and placeholders.
+// Placeholders are only recognized in SQL code, never inside quoted strings,
+// quoted identifiers, dollar-quoted bodies or comments, and a :: cast is never
+// mistaken for the start of a named parameter.
+func lex(query string) []token {
+	return lexSyntax(query, mysqlSyntax)
+}
+
+// lexSyntax is lex for a query written in syn
+func lexSyntax(query string, syn syntax) []token {
+	l := lexer{src: query, syntax: syn}
+
+	l.run()
+
+	return l.tokens
+}
+
+// lexDialect is lex for a query that already uses the native placeholders of
+// d, which are returned as positional tokens
+func lexDialect(query string, d Dialect) []token {
+	l := lexer{src: query, syntax: syntaxOf(d), numbered: placeholderPrefix(d)}
+
+	l.run()
+
+	return l.tokens
+}
+
+type lexer struct {
+	syntax
+	src      string
+	numbered string
+	start    int
+	pos      int
+	tokens   []token
+	// openComment is set when a line comment runs to the end of src
+	openComment bool
+}
+
+func (l *lexer) run() {
+	for l.pos < len(l.src) {
+		if l.numbered != "" && strings.HasPrefix(l.src[l.pos:], l.numbered) && isDigit(l.peek(len(l.numbered))) {
+			l.lexNumbered()
+			continue
+		}
+
+		switch c := l.src[l.pos]; {
+		case c == '\'' || c == '"' || c == '`':
+			l.skipQuoted(c)
+		case c == '-' && l.peek(1) == '-', c == '#' && l.hashComments:
+			l.skipLineComment()
+		case c == '/' && l.peek(1) == '*':
+			l.skipBlockComment()
+		case c == '$':
+			l.skipDollarQuoted()
+		case c == ':':
+			l.lexColon()
+		case c == '?' && l.numbered == "":
+			l.emit(tokenPositional, l.pos+1, "")
+		default:
+			l.pos++
+		}
+	}
+
+	if l.start < len(l.src) {
+		l.tokens = append(l.tokens, token{kind: tokenSQL, text: l.src[l.start:]})
+	}
+}
+
+func (l *lexer) peek(n int) byte {
+	if l.pos+n >= len(l.src) {
+		return 0
+	}
+
+	return l.src[l.pos+n]
+}
+
+// emit flushes any pending SQL text and appends a placeholder token ending at end
+func (l *lexer) emit(kind tokenKind, end int, name string) {
+	if l.start < l.pos {
+		l.tokens = append(l.tokens, token{kind: tokenSQL, text: l.src[l.start:l.pos]})
+	}
+
+	l.tokens = append(l.tokens, token{kind: kind, text: l.src[l.pos:end], name: name})
+
+	l.start, l.pos = end, end
+}
+
+// skipQuoted skips a '...', "..." or `...` section.  The quote character is
+// escaped by doubling it and, outside backquotes, by a backslash when the
+// syntax has backslash escapes or the string is a PostgreSQL E'...' escape
+// string.
+func (l *lexer) skipQuoted(quote byte) {
+	escapes := quote != '`' && (l.backslashEscapes || quote == '\'' && l.escapeString())
+
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		c := l.src[l.pos]
+
+		if c == '\\' && escapes {
+			l.pos++
+		} else if c == quote {
+			if l.peek(1) != quote {
+				l.pos++
+				return
+			}
+
+			l.pos++
+		}
+	}
+}
+
+// escapeString reports whether the quote at pos opens an E'...' string, that
+// is whether it follows an E that is not the end of a longer identifier
+func (l *lexer) escapeString() bool {
+	if l.pos == 0 || (l.src[l.pos-1] != 'E' && l.src[l.pos-1] != 'e') {
+		return false
+	}
+
+	return l.pos == 1 || !isIdentByte(l.src[l.pos-2])
+}
+
+func (l *lexer) skipLineComment() {
+	if i := strings.IndexByte(l.src[l.pos:], '\n'); i >= 0 {
+		l.pos += i + 1
+	} else {
+		l.pos = len(l.src)
+		l.openComment = true
+	}
+}
+
+// skipBlockComment skips a /* ... */ comment, honouring PostgreSQL nesting
+func (l *lexer) skipBlockComment() {
+	depth := 0
+
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '/' && l.peek(1) == '*' {
+			depth++
+			l.pos += 2
+		} else if l.src[l.pos] == '*' && l.peek(1) == '/' {
+			depth--
+			l.pos += 2
+
+			if depth == 0 {
+				return
+			}
+		} else {
+			l.pos++
+		}
+	}
+}
+
+// skipDollarQuoted skips a PostgreSQL $tag$ ... $tag$ body.  A $ that does not
+// open a dollar quote (e.g. $1 or part of an identifier) is skipped alone.
+func (l *lexer) skipDollarQuoted() {
+	if l.pos > 0 && isIdentByte(l.src[l.pos-1]) {
+		l.pos++
+		return
+	}
+
+	end := l.pos + 1
+	for end < len(l.src) && isIdentByte(l.src[end]) {
+		end++
+	}
+
+	if end >= len(l.src) || l.src[end] != '$' || (end > l.pos+1 && isDigit(l.src[l.pos+1])) {
+		l.pos++
+		return
+	}
+
+	tag := l.src[l.pos : end+1]
+
+	if i := strings.Index(l.src[end+1:], tag); i >= 0 {
+		l.pos = end + 1 + i + len(tag)
+	} else {
+		l.pos = len(l.src)
+	}
+}
+
+func (l *lexer) lexNumbered() {
+	start := l.pos + len(l.numbered)
+
+	end := start
+	for end < len(l.src) && isDigit(l.src[end]) {
+		end++
+	}
+
+	l.emit(tokenPositional, end, l.src[start:end])
+}
+
+// lexColon handles a ':' in SQL code, which is either a :: cast, the start of
+// a :name: placeholder or an ordinary character
+func (l *lexer) lexColon() {
+	if l.peek(1) == ':' {
+		l.pos += 2
+		return
+	}
+
+	end := l.pos + 1
+	for end < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[end:])
+
+		if !isNameRune(r, end == l.pos+1) {
+			break
+		}
+
+		end += size
+	}
+
+	if end > l.pos+1 && end < len(l.src) && l.src[end] == ':' {
+		l.emit(tokenNamed, end+1, l.src[l.pos+1:end])
+		return
+	}
+
+	l.pos++
+}
+
+// isNameRune reports whether r may appear in a parameter name.  Names start
+// with a letter or underscore, and the dot lets BindStruct address the fields
+// of nested structs.
+func isNameRune(r rune, first bool) bool {
+	if r == '_' || unicode.IsLetter(r) {
+		return true
+	}
+
+	return !first && (r == '.' || unicode.IsDigit(r))
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 0x80 || isDigit(c) || (c|0x20 >= 'a' && c|0x20 <= 'z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,151 @@
+package gdo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLexIgnoresNonCode(t *testing.T) {
+	cases := []map[string]interface{}{
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo WHERE t = '12:30:00' AND id = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo WHERE s = 'it''s :a: here' AND id = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo WHERE s = 'it\\'s :a:' AND id = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT `a:b:c` FROM Foo WHERE \"x:y:z\" = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo -- note: x:\nWHERE id = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo # note: x:\nWHERE id = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo /* :a: /* :b: */ :c: */ WHERE id = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT $$ :a: $$, $fn$ :b: $fn$ FROM Foo WHERE id = :id:",
+			"expected": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT id::text FROM Foo WHERE id = :id::int AND b = :b:",
+			"expected": []string{"id", "b"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT doc->'a:b:' FROM Foo WHERE id = $1 AND name = :name:",
+			"expected": []string{"name"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo WHERE t = 12:30:00",
+			"expected": []string(nil),
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo WHERE s = 'unterminated :a:",
+			"expected": []string(nil),
+		},
+	}
+
+	for _, c := range cases {
+		var names []string
+
+		for _, tok := range lex(c["query"].(string)) {
+			if tok.kind == tokenNamed {
+				names = append(names, tok.name)
+			}
+		}
+
+		assert.Equal(t, c["expected"], names, c["query"].(string))
+	}
+}
+
+func TestLexSyntax(t *testing.T) {
+	cases := []map[string]interface{}{
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo WHERE p = 'C:\\' AND id = :id:",
+			"mysql":    []string(nil),
+			"standard": []string{"id"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT * FROM Foo WHERE s = 'it\\'s :a:' AND id = :id:",
+			"mysql":    []string{"id"},
+			"standard": []string{"a"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT E'it\\'s :b: ok', e'\\\\', :a:",
+			"mysql":    []string{"a"},
+			"standard": []string{"a"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT name'x\\', :a:",
+			"mysql":    []string(nil),
+			"standard": []string{"a"},
+		},
+		map[string]interface{}{
+			"query":    "SELECT a #>> '{b}' FROM Foo WHERE id = :id:",
+			"mysql":    []string(nil),
+			"standard": []string{"id"},
+		},
+	}
+
+	names := func(query string, syn syntax) []string {
+		var names []string
+
+		for _, tok := range lexSyntax(query, syn) {
+			if tok.kind == tokenNamed {
+				names = append(names, tok.name)
+			}
+		}
+
+		return names
+	}
+
+	for _, c := range cases {
+		q := c["query"].(string)
+
+		assert.Equal(t, c["mysql"], names(q, mysqlSyntax), q)
+		assert.Equal(t, c["standard"], names(q, syntax{}), q)
+	}
+}
+
+func TestLexRoundTrip(t *testing.T) {
+	queries := []string{
+		"SELECT * FROM Foo WHERE id = :id: AND s = '?' AND b = ?",
+		"SELECT $tag$ body $tag$, 'a''b', `c` /* d */ -- e",
+		"SELECT * FROM Foo WHERE s = 'unterminated",
+	}
+
+	for _, q := range queries {
+		var s string
+
+		for _, tok := range lex(q) {
+			s += tok.text
+		}
+
+		assert.Equal(t, q, s)
+	}
+}
+
+func TestLexPositional(t *testing.T) {
+	var count int
+
+	for _, tok := range lex("SELECT '?', `?`, ? FROM Foo -- ?\nWHERE a = ?") {
+		if tok.kind == tokenPositional {
+			count++
+		}
+	}
+
+	assert.Equal(t, 2, count)
+}
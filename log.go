@@ -0,0 +1,76 @@
+package gdo
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogOptions configures LogInterceptor
+type LogOptions struct {
+	// Level is the level statements are logged at.  Failed statements are
+	// always logged at slog.LevelError.
+	Level slog.Level
+	// Interpolate adds the statement with its arguments interpolated, with
+	// sensitive values redacted, as the "query" attribute
+	Interpolate bool
+}
+
+// LogInterceptor returns an Interceptor that logs one record per statement to
+// logger, with the normalized SQL, the names of its parameters, its duration,
+// the rows it affected and its error
+func LogInterceptor(logger *slog.Logger, opts LogOptions) Interceptor {
+	return func(ctx context.Context, e *Execution, next func(ctx context.Context) error) error {
+		err := next(ctx)
+
+		level := opts.Level
+		if err != nil {
+			level = slog.LevelError
+		}
+
+		if !logger.Enabled(ctx, level) {
+			return err
+		}
+
+		attrs := []slog.Attr{
+			slog.String("sql", normalize(e.Statement.Query(), syntaxOf(e.cfg.getDialect()))),
+		}
+
+		if opts.Interpolate {
+			attrs = append(attrs, slog.String("query", e.LastExecutedQuery()))
+		}
+
+		if names := paramNames(e.Statement); len(names) > 0 {
+			attrs = append(attrs, slog.Any("params", names))
+		}
+
+		attrs = append(attrs, slog.Duration("duration", e.Duration))
+
+		if e.Op == OpExec && err == nil {
+			attrs = append(attrs, slog.Int64("rows_affected", e.RowsAffected))
+		}
+
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+
+		logger.LogAttrs(ctx, level, "gdo: "+string(e.Op), attrs...)
+
+		return err
+	}
+}
+
+// paramNames returns the names bound to s, each once in order of binding
+func paramNames(s *Statement) []string {
+	var names []string
+
+	seen := make(map[string]bool, len(s.namedArgs))
+
+	for _, arg := range s.namedArgs {
+		if !seen[arg.Name] {
+			seen[arg.Name] = true
+			names = append(names, arg.Name)
+		}
+	}
+
+	return names
+}
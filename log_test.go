@@ -0,0 +1,140 @@
+package gdo
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestLogInterceptor(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE Users SET password = ?, api_token = ? WHERE name = ? AND id IN (?, ?)")).
+		WithArgs("hunter2", "abc", "bob", 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT").WillReturnError(errors.New("fail"))
+
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	g := New(db,
+		WithRedact("password", "TOKEN"),
+		WithInterceptors(LogInterceptor(logger, LogOptions{Level: slog.LevelInfo, Interpolate: true})),
+	)
+
+	s := NewStatement("UPDATE Users SET password = :password:, api_token = :api_token: WHERE name = :name: AND id IN (:ids:)")
+	s.BindNamedArgs([]sql.NamedArg{
+		sql.Named("password", "hunter2"),
+		sql.Named("api_token", "abc"),
+		sql.Named("name", "bob"),
+		sql.Named("ids", Sensitive([]int{1, 2})),
+	})
+
+	r, err := g.Exec(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE Users SET password = [REDACTED], api_token = [REDACTED] WHERE name = 'bob' AND id IN ([REDACTED], [REDACTED])", r.LastExecutedQuery())
+
+	_, err = g.Query(NewStatement("SELECT 1"))
+	assert.Error(t, err)
+
+	dec := json.NewDecoder(&buf)
+
+	var rec map[string]interface{}
+
+	assert.NoError(t, dec.Decode(&rec))
+	assert.Equal(t, "INFO", rec["level"])
+	assert.Equal(t, "gdo: exec", rec["msg"])
+	assert.Equal(t, "UPDATE Users SET password = ?, api_token = ? WHERE name = ? AND id IN (?)", rec["sql"])
+	assert.Equal(t, r.LastExecutedQuery(), rec["query"])
+	assert.Equal(t, []interface{}{"password", "api_token", "name", "ids"}, rec["params"])
+	assert.Equal(t, float64(2), rec["rows_affected"])
+	assert.Contains(t, rec, "duration")
+	assert.NotContains(t, buf.String(), "hunter2")
+
+	rec = nil
+
+	assert.NoError(t, dec.Decode(&rec))
+	assert.Equal(t, "ERROR", rec["level"])
+	assert.Equal(t, "gdo: query", rec["msg"])
+	assert.Equal(t, "fail", rec["error"])
+	assert.NotContains(t, rec, "rows_affected")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSensitive(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta("SELECT * FROM Users WHERE ssn = ?")).
+		ExpectQuery().WithArgs("123").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM Users WHERE ssn = ?")).
+		WithArgs("456").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	g := New(db)
+
+	ps, err := g.Prepare("SELECT * FROM Users WHERE ssn = :ssn:")
+	assert.NoError(t, err)
+
+	r, err := ps.QueryArgs(context.Background(), sql.Named("ssn", Sensitive("123")))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM Users WHERE ssn = [REDACTED]", r.LastExecutedQuery())
+
+	s := NewStatement("DELETE FROM Users WHERE ssn = ?")
+	s.BindArg(Sensitive("456"))
+
+	er, err := g.Exec(s)
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM Users WHERE ssn = [REDACTED]", er.LastExecutedQuery())
+
+	assert.Equal(t, "[REDACTED]", Sensitive("x").(interface{ String() string }).String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedactPrepared(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta("UPDATE u SET pw = ? WHERE id = ?")).
+		ExpectExec().WithArgs("hunter2", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	g := New(db, WithRedact("pw"))
+
+	ps, err := g.Prepare("UPDATE u SET pw = :pw: WHERE id = :id:")
+	assert.NoError(t, err)
+
+	// names the query does not have, or missing ones, are never executed
+	_, err = ps.ExecArgs(context.Background(), sql.Named("pw", "hunter2"), sql.Named("id", 1), sql.Named("extra", 5))
+	assert.Equal(t, ErrParameterMismatch, err)
+
+	_, err = ps.ExecArgs(context.Background(), sql.Named("pw", "hunter2"))
+	assert.Equal(t, ErrParameterMismatch, err)
+
+	r, err := ps.ExecArgs(context.Background(), sql.Named("pw", "hunter2"), sql.Named("id", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE u SET pw = [REDACTED] WHERE id = 1", r.LastExecutedQuery())
+
+	// args that cannot be matched to names are all redacted
+	s := &Statement{
+		query:     "UPDATE u SET pw = ? WHERE id = ?",
+		namedArgs: []sql.NamedArg{sql.Named("pw", "hunter2"), sql.Named("extra", 5)},
+		args:      []interface{}{"hunter2", 1, 5},
+		tmpl:      compile("UPDATE u SET pw = :pw: WHERE id = :id:"),
+	}
+	assert.Equal(t, "UPDATE u SET pw = [REDACTED] WHERE id = [REDACTED]", s.lastExecutedQuery(g.cfg))
+
+	s.args = s.args[:2]
+	assert.Equal(t, "UPDATE u SET pw = [REDACTED] WHERE id = 1", s.lastExecutedQuery(g.cfg))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
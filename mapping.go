@@ -0,0 +1,317 @@
+package gdo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var ErrUnmappedColumn = errors.New("gdo: column does not map to a field")
+var ErrMissingColumn = errors.New("gdo: field has no column in the result")
+
+// NameMapper returns the column name for a struct field without a gdo tag
+type NameMapper func(field string) string
+
+// SnakeCase is a NameMapper mapping UserID to user_id and HTTPStatus to
+// http_status
+func SnakeCase(field string) string {
+	runes := []rune(field)
+
+	var sb strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// a new word starts at an upper case letter following a lower
+			// case one, or at the last upper case letter of an acronym
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteByte('_')
+			}
+
+			r = unicode.ToLower(r)
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+// WithNameMapper sets how FetchRowsTyped and the Fetch functions derive a
+// column name from a field without a gdo tag.  By default the column must
+// equal the field name, ignoring case.
+func WithNameMapper(m NameMapper) Option {
+	return func(c *config) {
+		c.mapper = &nameMapper{fn: m}
+	}
+}
+
+// WithStrictMapping makes FetchRowsTyped and the Fetch functions fail with
+// ErrUnmappedColumn for a column that maps to no field, and with
+// ErrMissingColumn for a field that no column maps to
+func WithStrictMapping() Option {
+	return func(c *config) {
+		c.strictMapping = true
+	}
+}
+
+// nameMapper caches the structMapping of each struct type for a NameMapper
+type nameMapper struct {
+	fn       NameMapper
+	mappings sync.Map // reflect.Type -> *structMapping
+}
+
+var defaultMapper = &nameMapper{}
+
+func (c *config) getMapper() *nameMapper {
+	if c == nil || c.mapper == nil {
+		return defaultMapper
+	}
+
+	return c.mapper
+}
+
+func (c *config) getStrictMapping() bool {
+	return c != nil && c.strictMapping
+}
+
+// structMapping holds the fields of a struct type that columns can map to.
+// Fields of embedded structs are promoted as in Go, and those of other nested
+// structs are prefixed with the column of the struct field and a dot.
+type structMapping struct {
+	fields []mappedField
+	// byTag and byColumn hold positions in fields
+	byTag    map[string]int
+	byColumn map[string]int
+}
+
+type mappedField struct {
+	column string
+	index  []int
+}
+
+// candidate is the field found so far for a column while walking a struct
+type candidate struct {
+	field mappedField
+	tag   string
+	depth int
+	// ambiguous is set when more than one field is at depth
+	ambiguous bool
+}
+
+func (nm *nameMapper) mappingFor(t reflect.Type) *structMapping {
+	if m, ok := nm.mappings.Load(t); ok {
+		return m.(*structMapping)
+	}
+
+	m := &structMapping{
+		byTag:    make(map[string]int),
+		byColumn: make(map[string]int),
+	}
+
+	found := make(map[string]*candidate)
+	var keys []string
+
+	nm.addFields(t, nil, "", map[reflect.Type]bool{}, found, &keys)
+
+	for _, key := range keys {
+		c := found[key]
+
+		if c.ambiguous {
+			continue
+		}
+
+		if c.tag != "" {
+			m.byTag[c.tag] = len(m.fields)
+		}
+
+		m.byColumn[key] = len(m.fields)
+		m.fields = append(m.fields, c.field)
+	}
+
+	actual, _ := nm.mappings.LoadOrStore(t, m)
+
+	return actual.(*structMapping)
+}
+
+// addFields records the fields of t in found by the lower case of their
+// column, appending each new column to keys.  As in Go, a shallower field
+// hides deeper ones, and fields at the same depth hide each other.  path
+// holds the struct types being walked, so that a struct referring to itself
+// is not walked again.
+func (nm *nameMapper) addFields(t reflect.Type, index []int, prefix string, path map[reflect.Type]bool, found map[string]*candidate, keys *[]string) {
+	path[t] = true
+	defer delete(path, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		// unexported embedded structs still promote their exported fields,
+		// unless they would have to be allocated
+		if f.PkgPath != "" && (!f.Anonymous || f.Type.Kind() == reflect.Ptr) {
+			continue
+		}
+
+		tag := f.Tag.Get("gdo")
+
+		if tag == "-" {
+			continue
+		}
+
+		idx := append(append([]int(nil), index...), i)
+
+		if !isScanLeaf(f.Type) {
+			st := f.Type
+			if st.Kind() == reflect.Ptr {
+				st = st.Elem()
+			}
+
+			if path[st] {
+				continue
+			}
+
+			if f.Anonymous && tag == "" {
+				nm.addFields(st, idx, prefix, path, found, keys)
+			} else {
+				nm.addFields(st, idx, prefix+nm.column(f.Name, tag)+".", path, found, keys)
+			}
+
+			continue
+		}
+
+		if f.PkgPath != "" {
+			continue
+		}
+
+		col := prefix + nm.column(f.Name, tag)
+		key := strings.ToLower(col)
+
+		c := &candidate{field: mappedField{column: col, index: idx}, depth: len(idx)}
+
+		if tag != "" {
+			c.tag = prefix + tag
+		}
+
+		old, ok := found[key]
+
+		switch {
+		case !ok:
+			*keys = append(*keys, key)
+			found[key] = c
+		case c.depth < old.depth:
+			found[key] = c
+		case c.depth == old.depth:
+			old.ambiguous = true
+		}
+	}
+}
+
+func (nm *nameMapper) column(field, tag string) string {
+	if tag != "" {
+		return tag
+	}
+
+	if nm.fn == nil {
+		return field
+	}
+
+	return nm.fn(field)
+}
+
+// field returns the position in m.fields of the field col maps to, matching
+// a gdo tag first and then the column of each field, ignoring case
+func (m *structMapping) field(col string) (int, bool) {
+	if i, ok := m.byTag[col]; ok {
+		return i, true
+	}
+
+	i, ok := m.byColumn[strings.ToLower(col)]
+
+	return i, ok
+}
+
+// plan returns the index of the field each of cols is scanned into, nil for a
+// column that is dropped
+func (m *structMapping) plan(cols []string, strict bool) ([][]int, error) {
+	fields := make([][]int, len(cols))
+	used := make([]bool, len(m.fields))
+
+	for i, col := range cols {
+		f, ok := m.field(col)
+
+		if !ok {
+			if strict {
+				return nil, fmt.Errorf("%w: %s", ErrUnmappedColumn, col)
+			}
+
+			continue
+		}
+
+		fields[i] = m.fields[f].index
+		used[f] = true
+	}
+
+	if strict {
+		for f, u := range used {
+			if !u {
+				return nil, fmt.Errorf("%w: %s", ErrMissingColumn, m.fields[f].column)
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// isScanLeaf reports whether a field of type t is scanned into as a single
+// value rather than descended into
+func isScanLeaf(t reflect.Type) bool {
+	if t.Implements(scannerType) || reflect.PtrTo(t).Implements(scannerType) {
+		return true
+	}
+
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() != reflect.Struct || t == timeType || reflect.PtrTo(t).Implements(scannerType)
+}
+
+// scanDest returns pointers to the fields of v, a struct, that the columns of
+// a row are scanned into, allocating any nil embedded pointers on the way.
+// Columns without a field are scanned into sink.
+func scanDest(v reflect.Value, fields [][]int, sink []interface{}) []interface{} {
+	dest := make([]interface{}, len(fields))
+
+	for i, idx := range fields {
+		if idx == nil {
+			dest[i] = &sink[i]
+			continue
+		}
+
+		dest[i] = fieldByIndex(v, idx).Addr().Interface()
+	}
+
+	return dest
+}
+
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.Field(x)
+	}
+
+	return v
+}
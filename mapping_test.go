@@ -0,0 +1,223 @@
+package gdo
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type MappedAudit struct {
+	CreatedAt time.Time
+	UpdatedBy *string
+}
+
+type mappedBase struct {
+	ID int64
+}
+
+type mappedAddress struct {
+	City string
+}
+
+type mappedUser struct {
+	mappedBase
+	*MappedAudit
+	UserName string
+	Email    sql.NullString `gdo:"email_address"`
+	Address  mappedAddress
+	Ignored  string `gdo:"-"`
+	secret   string
+}
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"ID":          "id",
+		"UserID":      "user_id",
+		"HTTPStatus":  "http_status",
+		"CreatedAt":   "created_at",
+		"Address2":    "address2",
+		"V2Name":      "v2_name",
+		"already_low": "already_low",
+	}
+
+	for field, expected := range cases {
+		assert.Equal(t, expected, SnakeCase(field), field)
+	}
+}
+
+func TestFetchRowsTyped(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Date(2024, 3, 9, 14, 5, 6, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"email_address", "address.city", "updated_by", "user_name", "created_at", "id", "extra"}).
+		AddRow("a@example.com", "Oslo", nil, "a", now, int64(1), "x").
+		AddRow(nil, "Rome", "admin", "b", now, int64(2), "y"))
+
+	r, err := New(db, WithNameMapper(SnakeCase)).Query(NewStatement("SELECT * FROM Users"))
+	assert.NoError(t, err)
+
+	result, err := r.FetchRowsTyped(&mappedUser{})
+	assert.NoError(t, err)
+
+	admin := "admin"
+
+	assert.Equal(t, []mappedUser{
+		{
+			mappedBase:  mappedBase{ID: 1},
+			MappedAudit: &MappedAudit{CreatedAt: now},
+			UserName:    "a",
+			Email:       sql.NullString{String: "a@example.com", Valid: true},
+			Address:     mappedAddress{City: "Oslo"},
+		},
+		{
+			mappedBase:  mappedBase{ID: 2},
+			MappedAudit: &MappedAudit{CreatedAt: now, UpdatedBy: &admin},
+			UserName:    "b",
+			Address:     mappedAddress{City: "Rome"},
+		},
+	}, result.([]mappedUser))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchRowsTypedDefaultNames(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"username", "ID", "Secret"}).
+		AddRow("a", int64(1), "s"))
+
+	r, err := New(db).Query(NewStatement("SELECT * FROM Users"))
+	assert.NoError(t, err)
+
+	result, err := r.FetchRowsTyped(&mappedUser{})
+	assert.NoError(t, err)
+	assert.Equal(t, []mappedUser{{mappedBase: mappedBase{ID: 1}, UserName: "a"}}, result.([]mappedUser))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStrictMapping(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "extra"}).AddRow(int64(1), "x"))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "user_name"}).AddRow(int64(1), "a"))
+
+	type user struct {
+		ID       int64
+		UserName string
+	}
+
+	g := New(db, WithNameMapper(SnakeCase), WithStrictMapping())
+
+	r, err := g.Query(NewStatement("SELECT id, extra FROM Users"))
+	assert.NoError(t, err)
+
+	_, err = r.FetchRowsTyped(&user{})
+	assert.True(t, errors.Is(err, ErrUnmappedColumn))
+	assert.EqualError(t, err, "gdo: column does not map to a field: extra")
+
+	r, err = g.Query(NewStatement("SELECT id FROM Users"))
+	assert.NoError(t, err)
+
+	_, err = FetchAll[user](r)
+	assert.True(t, errors.Is(err, ErrMissingColumn))
+	assert.EqualError(t, err, "gdo: field has no column in the result: user_name")
+
+	r, err = g.Query(NewStatement("SELECT id, user_name FROM Users"))
+	assert.NoError(t, err)
+
+	users, err := FetchAll[user](r)
+	assert.NoError(t, err)
+	assert.Equal(t, []user{{ID: 1, UserName: "a"}}, users)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type MappedInner struct {
+	ID   int64
+	Note string
+}
+
+type MappedOther struct {
+	Note string
+}
+
+type MappedDeep struct {
+	MappedInner
+}
+
+type MappedCategory struct {
+	ID     int64
+	Name   string
+	Parent *MappedCategory
+	Meta   struct {
+		Root *MappedCategory
+		Tag  string
+	}
+}
+
+func TestStructMappingSelfReference(t *testing.T) {
+	m := defaultMapper.mappingFor(reflect.TypeOf(MappedCategory{}))
+
+	fields, err := m.plan([]string{"id", "name", "meta.tag"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{0}, {1}, {3, 1}}, fields)
+}
+
+func TestStructMappingShadowing(t *testing.T) {
+	type shadowed struct {
+		MappedInner
+		ID   int64
+		Name string
+	}
+
+	m := defaultMapper.mappingFor(reflect.TypeOf(shadowed{}))
+
+	fields, err := m.plan([]string{"id", "name", "note"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{1}, {2}, {0, 1}}, fields)
+
+	// the deeper ID is replaced rather than left to be reported missing
+	_, err = m.plan([]string{"id", "name"}, true)
+	assert.True(t, errors.Is(err, ErrMissingColumn))
+	assert.EqualError(t, err, "gdo: field has no column in the result: Note")
+
+	// fields at the same depth are ambiguous, and hide deeper ones
+	type ambiguous struct {
+		MappedInner
+		MappedOther
+		MappedDeep
+	}
+
+	m = defaultMapper.mappingFor(reflect.TypeOf(ambiguous{}))
+
+	fields, err = m.plan([]string{"id", "note"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{0, 0}, nil}, fields)
+
+	_, err = m.plan([]string{"note"}, true)
+	assert.True(t, errors.Is(err, ErrUnmappedColumn))
+
+	// a shallower field makes an ambiguous name unambiguous
+	type resolved struct {
+		MappedInner
+		MappedOther
+		Note string
+	}
+
+	m = defaultMapper.mappingFor(reflect.TypeOf(resolved{}))
+
+	fields, err = m.plan([]string{"id", "note"}, true)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{0, 0}, {2}}, fields)
+}
@@ -0,0 +1,266 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram
+// of a Metrics created without buckets
+var DefaultBuckets = []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// maxFingerprints bounds the cache of normalized queries kept by a Metrics
+const maxFingerprints = 1024
+
+// Metrics counts the statements run through a GDO and times them, by
+// fingerprint (the query as returned by Normalize), operation and outcome.
+// It serves them, along with the pool statistics of the GDO, in the
+// Prometheus text format.
+type Metrics struct {
+	buckets []float64
+
+	mu           sync.Mutex
+	counts       map[metricKey]uint64
+	latencies    map[latencyKey]*histogram
+	fingerprints map[fingerprintKey]string
+	db           *sql.DB
+}
+
+type metricKey struct {
+	fingerprint string
+	op          Operation
+	outcome     string
+}
+
+// fingerprintKey identifies a query as written in a syntax
+type fingerprintKey struct {
+	query  string
+	syntax syntax
+}
+
+type latencyKey struct {
+	fingerprint string
+	op          Operation
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewMetrics returns a Metrics timing statements in a histogram with the
+// given upper bounds in seconds, or DefaultBuckets when there are none
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Metrics{
+		buckets:      buckets,
+		counts:       make(map[metricKey]uint64),
+		latencies:    make(map[latencyKey]*histogram),
+		fingerprints: make(map[fingerprintKey]string),
+	}
+}
+
+// WithMetrics records the statements of the GDO in m.  The pool statistics m
+// reports are those of the first GDO created with it.
+func WithMetrics(m *Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+		c.interceptors = append(c.interceptors, m.intercept)
+	}
+}
+
+func (m *Metrics) intercept(ctx context.Context, e *Execution, next func(ctx context.Context) error) error {
+	err := next(ctx)
+
+	m.observe(e.Op, e.Statement.Query(), syntaxOf(e.cfg.getDialect()), outcome(err), e.Duration)
+
+	return err
+}
+
+// outcome classifies the result of a statement as "ok", "canceled" when its
+// context ended first, or "error"
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
+
+func (m *Metrics) observe(op Operation, query string, syn syntax, outcome string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fp, ok := m.fingerprints[fingerprintKey{query, syn}]
+
+	if !ok {
+		if len(m.fingerprints) >= maxFingerprints {
+			m.fingerprints = make(map[fingerprintKey]string)
+		}
+
+		fp = normalize(query, syn)
+		m.fingerprints[fingerprintKey{query, syn}] = fp
+	}
+
+	m.counts[metricKey{fp, op, outcome}]++
+
+	h, ok := m.latencies[latencyKey{fp, op}]
+
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[latencyKey{fp, op}] = h
+	}
+
+	s := d.Seconds()
+
+	for i, le := range m.buckets {
+		if s <= le {
+			h.counts[i]++
+		}
+	}
+
+	h.sum += s
+	h.count++
+}
+
+func (m *Metrics) observeDB(db *sql.DB) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.db == nil {
+		m.db = db
+	}
+}
+
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics to w in the Prometheus text format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	m.mu.Lock()
+
+	counts := make([]metricKey, 0, len(m.counts))
+	for k := range m.counts {
+		counts = append(counts, k)
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		x, y := counts[i], counts[j]
+
+		if x.fingerprint != y.fingerprint {
+			return x.fingerprint < y.fingerprint
+		}
+
+		if x.op != y.op {
+			return x.op < y.op
+		}
+
+		return x.outcome < y.outcome
+	})
+
+	b.WriteString("# HELP gdo_statements_total Statements run, by fingerprint, operation and outcome.\n")
+	b.WriteString("# TYPE gdo_statements_total counter\n")
+
+	for _, k := range counts {
+		fmt.Fprintf(&b, "gdo_statements_total{fingerprint=%s,op=%s,outcome=%s} %d\n",
+			labelValue(k.fingerprint), labelValue(string(k.op)), labelValue(k.outcome), m.counts[k])
+	}
+
+	latencies := make([]latencyKey, 0, len(m.latencies))
+	for k := range m.latencies {
+		latencies = append(latencies, k)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		x, y := latencies[i], latencies[j]
+
+		if x.fingerprint != y.fingerprint {
+			return x.fingerprint < y.fingerprint
+		}
+
+		return x.op < y.op
+	})
+
+	b.WriteString("# HELP gdo_statement_duration_seconds Time taken by statements, by fingerprint and operation.\n")
+	b.WriteString("# TYPE gdo_statement_duration_seconds histogram\n")
+
+	for _, k := range latencies {
+		h := m.latencies[k]
+		labels := "fingerprint=" + labelValue(k.fingerprint) + ",op=" + labelValue(string(k.op))
+
+		for i, le := range m.buckets {
+			fmt.Fprintf(&b, "gdo_statement_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, formatFloat(le), h.counts[i])
+		}
+
+		fmt.Fprintf(&b, "gdo_statement_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, h.count)
+		fmt.Fprintf(&b, "gdo_statement_duration_seconds_sum{%s} %s\n", labels, formatFloat(h.sum))
+		fmt.Fprintf(&b, "gdo_statement_duration_seconds_count{%s} %d\n", labels, h.count)
+	}
+
+	db := m.db
+
+	m.mu.Unlock()
+
+	if db != nil {
+		writeDBStats(&b, db.Stats())
+	}
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+func writeDBStats(b *strings.Builder, s sql.DBStats) {
+	metrics := []struct {
+		name, kind, help string
+		value            float64
+	}{
+		{"gdo_pool_max_open_connections", "gauge", "Maximum number of open connections to the database.", float64(s.MaxOpenConnections)},
+		{"gdo_pool_open_connections", "gauge", "Established connections, both in use and idle.", float64(s.OpenConnections)},
+		{"gdo_pool_in_use_connections", "gauge", "Connections currently in use.", float64(s.InUse)},
+		{"gdo_pool_idle_connections", "gauge", "Idle connections.", float64(s.Idle)},
+		{"gdo_pool_wait_count_total", "counter", "Connections waited for.", float64(s.WaitCount)},
+		{"gdo_pool_wait_duration_seconds_total", "counter", "Time blocked waiting for a new connection.", s.WaitDuration.Seconds()},
+		{"gdo_pool_max_idle_closed_total", "counter", "Connections closed due to SetMaxIdleConns.", float64(s.MaxIdleClosed)},
+		{"gdo_pool_max_idle_time_closed_total", "counter", "Connections closed due to SetConnMaxIdleTime.", float64(s.MaxIdleTimeClosed)},
+		{"gdo_pool_max_lifetime_closed_total", "counter", "Connections closed due to SetConnMaxLifetime.", float64(s.MaxLifetimeClosed)},
+	}
+
+	for _, m := range metrics {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", m.name, m.help, m.name, m.kind, m.name, formatFloat(m.value))
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func labelValue(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,71 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestMetrics(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM Foo WHERE id IN (?)")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM Foo WHERE id IN (?, ?)")).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT").WillReturnError(errors.New("fail"))
+	mock.ExpectPrepare("UPDATE")
+
+	m := NewMetrics(0.5, 0.1)
+	g := New(db, WithMetrics(m))
+
+	for _, ids := range [][]int{{1}, {1, 2}} {
+		s := NewStatement("DELETE FROM Foo WHERE id IN (:ids:)")
+		s.BindNamedArg(sql.Named("ids", ids))
+
+		_, err := g.Exec(s)
+		assert.NoError(t, err)
+	}
+
+	_, err := g.Query(NewStatement("SELECT \"a\nb\" FROM Foo"))
+	assert.Error(t, err)
+
+	_, err = g.Prepare("UPDATE Foo SET a = :a:")
+	assert.NoError(t, err)
+
+	m.observe(OpQuery, "SELECT 1", mysqlSyntax, outcome(context.DeadlineExceeded), 200*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	body := w.Body.String()
+
+	expected := []string{
+		`gdo_statements_total{fingerprint="DELETE FROM Foo WHERE id IN (?)",op="exec",outcome="ok"} 2`,
+		`gdo_statements_total{fingerprint="SELECT \"a\nb\" FROM Foo",op="query",outcome="error"} 1`,
+		`gdo_statements_total{fingerprint="SELECT ?",op="query",outcome="canceled"} 1`,
+		`gdo_statements_total{fingerprint="UPDATE Foo SET a = ?",op="prepare",outcome="ok"} 1`,
+		`gdo_statement_duration_seconds_bucket{fingerprint="DELETE FROM Foo WHERE id IN (?)",op="exec",le="0.1"} 2`,
+		`gdo_statement_duration_seconds_bucket{fingerprint="SELECT ?",op="query",le="0.1"} 0`,
+		`gdo_statement_duration_seconds_bucket{fingerprint="SELECT ?",op="query",le="0.5"} 1`,
+		`gdo_statement_duration_seconds_bucket{fingerprint="SELECT ?",op="query",le="+Inf"} 1`,
+		`gdo_statement_duration_seconds_sum{fingerprint="SELECT ?",op="query"} 0.2`,
+		`gdo_statement_duration_seconds_count{fingerprint="DELETE FROM Foo WHERE id IN (?)",op="exec"} 2`,
+		"# TYPE gdo_pool_open_connections gauge\ngdo_pool_open_connections 1",
+		"# TYPE gdo_pool_wait_count_total counter\ngdo_pool_wait_count_total 0",
+	}
+
+	for _, e := range expected {
+		assert.Contains(t, body, e+"\n")
+	}
+
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain; version=0.0.4"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
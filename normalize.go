@@ -0,0 +1,145 @@
+package gdo
+
+import "strings"
+
+// Normalize returns the fingerprint of query: literals and placeholders are
+// replaced by ?, lists of them collapsed to a single ?, comments dropped and
+// whitespace collapsed.  Executions of the same statement with different
+// values, or IN lists of different lengths, normalize to the same string.
+func Normalize(query string) string {
+	return normalize(query, mysqlSyntax)
+}
+
+// normalize is Normalize for a query written in syn
+func normalize(query string, syn syntax) string {
+	n := normalizer{lexer: lexer{src: query, syntax: syn}}
+
+	n.run()
+
+	return string(n.buf)
+}
+
+type normalizer struct {
+	lexer
+	buf   []byte
+	space bool
+}
+
+func (n *normalizer) run() {
+	for n.pos < len(n.src) {
+		start := n.pos
+
+		switch c := n.src[n.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			n.pos++
+			n.space = true
+		case c == '-' && n.peek(1) == '-', c == '#' && n.hashComments:
+			n.skipLineComment()
+			n.space = true
+		case c == '/' && n.peek(1) == '*':
+			n.skipBlockComment()
+			n.space = true
+		case c == '\'':
+			n.skipQuoted(c)
+			n.value()
+		case c == '"' || c == '`':
+			n.skipQuoted(c)
+			n.write(n.src[start:n.pos])
+		case c == '$':
+			n.skipDollarQuoted()
+
+			if n.pos > start+1 || isDigit(n.peek(0)) {
+				n.skipIdent()
+				n.value()
+			} else {
+				n.write("$")
+			}
+		case c == '?':
+			n.pos++
+			n.value()
+		case c == ':':
+			n.colon()
+		case c == '@' && n.peek(1) == 'p' && isDigit(n.peek(2)):
+			n.pos += 2
+			n.skipIdent()
+			n.value()
+		case isDigit(c) || (c == '.' && isDigit(n.peek(1))):
+			n.skipNumber()
+			n.value()
+		case isIdentByte(c):
+			n.skipIdent()
+
+			if n.pos == start+1 && n.peek(0) == '\'' && n.escapeString() {
+				n.skipQuoted('\'')
+				n.value()
+				continue
+			}
+
+			n.write(n.src[start:n.pos])
+		default:
+			n.pos++
+			n.write(n.src[start:n.pos])
+		}
+	}
+}
+
+// colon handles a :: cast, a :name: or :1 placeholder, or a lone ':'
+func (n *normalizer) colon() {
+	if n.peek(1) == ':' {
+		n.pos += 2
+		n.write("::")
+		return
+	}
+
+	n.lexColon()
+
+	if len(n.tokens) > 0 {
+		n.tokens = n.tokens[:0]
+		n.value()
+		return
+	}
+
+	if isDigit(n.peek(0)) {
+		n.skipIdent()
+		n.value()
+		return
+	}
+
+	n.write(":")
+}
+
+func (n *normalizer) skipIdent() {
+	for n.pos < len(n.src) && isIdentByte(n.src[n.pos]) {
+		n.pos++
+	}
+}
+
+// skipNumber skips a numeric literal such as 42, 1.5, 1e10 or 0x1F
+func (n *normalizer) skipNumber() {
+	for n.pos < len(n.src) && (isIdentByte(n.src[n.pos]) || n.src[n.pos] == '.') {
+		n.pos++
+	}
+}
+
+func (n *normalizer) write(s string) {
+	if n.space && len(n.buf) > 0 {
+		n.buf = append(n.buf, ' ')
+	}
+
+	n.space = false
+	n.buf = append(n.buf, s...)
+}
+
+// value writes a ? for a literal or placeholder, unless it follows another
+// one in a list
+func (n *normalizer) value() {
+	b := strings.TrimRight(string(n.buf), " ")
+
+	if strings.HasSuffix(b, ",") && strings.HasSuffix(strings.TrimRight(b[:len(b)-1], " "), "?") {
+		n.buf = n.buf[:len(strings.TrimRight(b[:len(b)-1], " "))]
+		n.space = false
+		return
+	}
+
+	n.write("?")
+}
@@ -0,0 +1,56 @@
+package gdo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := []map[string]string{
+		{
+			"query":    "SELECT * FROM Foo WHERE id = 1",
+			"expected": "SELECT * FROM Foo WHERE id = ?",
+		},
+		{
+			"query":    "SELECT *\n\tFROM Foo   WHERE name = 'it''s' AND x = 1.5e3",
+			"expected": "SELECT * FROM Foo WHERE name = ? AND x = ?",
+		},
+		{
+			"query":    "SELECT * FROM Foo WHERE id IN (1, 2, 3) -- trailing",
+			"expected": "SELECT * FROM Foo WHERE id IN (?)",
+		},
+		{
+			"query":    "SELECT E'it\\'s', e FROM Foo",
+			"expected": "SELECT ?, e FROM Foo",
+		},
+		{
+			"query":    "SELECT * FROM Foo # trailing\nWHERE id = 1",
+			"expected": "SELECT * FROM Foo WHERE id = ?",
+		},
+		{
+			"query":    "SELECT * FROM Foo WHERE id IN (?, ?,?) AND b = ?",
+			"expected": "SELECT * FROM Foo WHERE id IN (?) AND b = ?",
+		},
+		{
+			"query":    "SELECT * FROM Foo WHERE a = $1 AND b = @p2 AND c = :3 AND d = :name:",
+			"expected": "SELECT * FROM Foo WHERE a = ? AND b = ? AND c = ? AND d = ?",
+		},
+		{
+			"query":    "SELECT a::text, /* note */ \"Col 1\", `t2`.b FROM t2",
+			"expected": "SELECT a::text, \"Col 1\", `t2`.b FROM t2",
+		},
+		{
+			"query":    "SELECT $$ body $$, $tag$ x $tag$ FROM t",
+			"expected": "SELECT ? FROM t",
+		},
+		{
+			"query":    "INSERT INTO Foo (a, b) VALUES ('x', 2)",
+			"expected": "INSERT INTO Foo (a, b) VALUES (?)",
+		},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c["expected"], Normalize(c["query"]), c["query"])
+	}
+}
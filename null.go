@@ -0,0 +1,155 @@
+package gdo
+
+import "time"
+
+// IsNull reports whether the column col holds NULL.  It reports false for a
+// column that is not in the row.
+func (r Row) IsNull(col string) bool {
+	v, ok := r[col]
+
+	return ok && v == nil
+}
+
+// nullable returns nil for a NULL column and otherwise what get returns
+func nullable[T any](r Row, col string, get func(string) (T, error)) (*T, error) {
+	if r.IsNull(col) {
+		return nil, nil
+	}
+
+	v, err := get(col)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// orDefault returns def for a NULL column and otherwise what get returns
+func orDefault[T any](r Row, col string, def T, get func(string) (T, error)) (T, error) {
+	if r.IsNull(col) {
+		return def, nil
+	}
+
+	return get(col)
+}
+
+// IntPtr is Int returning nil for NULL
+func (r Row) IntPtr(col string) (*int, error) {
+	return nullable(r, col, r.Int)
+}
+
+// IntOr is Int returning def for NULL
+func (r Row) IntOr(col string, def int) (int, error) {
+	return orDefault(r, col, def, r.Int)
+}
+
+// Int64Ptr is Int64 returning nil for NULL
+func (r Row) Int64Ptr(col string) (*int64, error) {
+	return nullable(r, col, r.Int64)
+}
+
+// Int64Or is Int64 returning def for NULL
+func (r Row) Int64Or(col string, def int64) (int64, error) {
+	return orDefault(r, col, def, r.Int64)
+}
+
+// Int32Ptr is Int32 returning nil for NULL
+func (r Row) Int32Ptr(col string) (*int32, error) {
+	return nullable(r, col, r.Int32)
+}
+
+// Int32Or is Int32 returning def for NULL
+func (r Row) Int32Or(col string, def int32) (int32, error) {
+	return orDefault(r, col, def, r.Int32)
+}
+
+// Uint64Ptr is Uint64 returning nil for NULL
+func (r Row) Uint64Ptr(col string) (*uint64, error) {
+	return nullable(r, col, r.Uint64)
+}
+
+// Uint64Or is Uint64 returning def for NULL
+func (r Row) Uint64Or(col string, def uint64) (uint64, error) {
+	return orDefault(r, col, def, r.Uint64)
+}
+
+// StringPtr is String returning nil for NULL
+func (r Row) StringPtr(col string) (*string, error) {
+	return nullable(r, col, r.String)
+}
+
+// StringOr is String returning def for NULL
+func (r Row) StringOr(col string, def string) (string, error) {
+	return orDefault(r, col, def, r.String)
+}
+
+// Float64Ptr is Float64 returning nil for NULL
+func (r Row) Float64Ptr(col string) (*float64, error) {
+	return nullable(r, col, r.Float64)
+}
+
+// Float64Or is Float64 returning def for NULL
+func (r Row) Float64Or(col string, def float64) (float64, error) {
+	return orDefault(r, col, def, r.Float64)
+}
+
+// Float32Ptr is Float32 returning nil for NULL
+func (r Row) Float32Ptr(col string) (*float32, error) {
+	return nullable(r, col, r.Float32)
+}
+
+// Float32Or is Float32 returning def for NULL
+func (r Row) Float32Or(col string, def float32) (float32, error) {
+	return orDefault(r, col, def, r.Float32)
+}
+
+// BoolPtr is Bool returning nil for NULL
+func (r Row) BoolPtr(col string) (*bool, error) {
+	return nullable(r, col, r.Bool)
+}
+
+// BoolOr is Bool returning def for NULL
+func (r Row) BoolOr(col string, def bool) (bool, error) {
+	return orDefault(r, col, def, r.Bool)
+}
+
+// BytesPtr is Bytes returning nil for NULL
+func (r Row) BytesPtr(col string) (*[]byte, error) {
+	return nullable(r, col, r.Bytes)
+}
+
+// BytesOr is Bytes returning def for NULL
+func (r Row) BytesOr(col string, def []byte) ([]byte, error) {
+	return orDefault(r, col, def, r.Bytes)
+}
+
+// TimePtr is Time returning nil for NULL
+func (r Row) TimePtr(col string) (*time.Time, error) {
+	return nullable(r, col, r.Time)
+}
+
+// TimeOr is Time returning def for NULL
+func (r Row) TimeOr(col string, def time.Time) (time.Time, error) {
+	return orDefault(r, col, def, r.Time)
+}
+
+// DatePtr is Date returning nil for NULL
+func (r Row) DatePtr(col string) (*time.Time, error) {
+	return nullable(r, col, r.Date)
+}
+
+// DateOr is Date returning def for NULL
+func (r Row) DateOr(col string, def time.Time) (time.Time, error) {
+	return orDefault(r, col, def, r.Date)
+}
+
+// DurationPtr is Duration returning nil for NULL
+func (r Row) DurationPtr(col string) (*time.Duration, error) {
+	return nullable(r, col, r.Duration)
+}
+
+// DurationOr is Duration returning def for NULL
+func (r Row) DurationOr(col string, def time.Duration) (time.Duration, error) {
+	return orDefault(r, col, def, r.Duration)
+}
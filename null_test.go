@@ -0,0 +1,152 @@
+package gdo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowIsNull(t *testing.T) {
+	r := Row{"a": nil, "b": int64(1)}
+
+	assert.True(t, r.IsNull("a"))
+	assert.False(t, r.IsNull("b"))
+	assert.False(t, r.IsNull("c"))
+}
+
+func TestRowNullErr(t *testing.T) {
+	r := Row{"a": nil}
+
+	cases := map[string]func() error{
+		"Int":      func() error { _, err := r.Int("a"); return err },
+		"Int64":    func() error { _, err := r.Int64("a"); return err },
+		"Int32":    func() error { _, err := r.Int32("a"); return err },
+		"Uint64":   func() error { _, err := r.Uint64("a"); return err },
+		"String":   func() error { _, err := r.String("a"); return err },
+		"Float64":  func() error { _, err := r.Float64("a"); return err },
+		"Float32":  func() error { _, err := r.Float32("a"); return err },
+		"Bool":     func() error { _, err := r.Bool("a"); return err },
+		"Bytes":    func() error { _, err := r.Bytes("a"); return err },
+		"Time":     func() error { _, err := r.Time("a"); return err },
+		"Date":     func() error { _, err := r.Date("a"); return err },
+		"Duration": func() error { _, err := r.Duration("a"); return err },
+	}
+
+	for name, fn := range cases {
+		err := fn()
+		assert.Equal(t, ErrNull, err, name)
+		assert.ErrorIs(t, err, ErrCannotConvert, name)
+	}
+}
+
+func TestRowPtr(t *testing.T) {
+	r := Row{"null": nil, "int": int64(7), "str": []byte("x"), "time": []byte("2017-01-02 03:04:05"), "bad": "x"}
+
+	i, err := r.IntPtr("null")
+	assert.NoError(t, err)
+	assert.Nil(t, i)
+
+	i, err = r.IntPtr("int")
+	assert.NoError(t, err)
+	assert.Equal(t, 7, *i)
+
+	i, err = r.IntPtr("bad")
+	assert.Equal(t, ErrCannotConvert, err)
+	assert.Nil(t, i)
+
+	s, err := r.StringPtr("str")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", *s)
+
+	tm, err := r.TimePtr("time")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 1, 2, 3, 4, 5, 0, time.UTC), *tm)
+
+	tm, err = r.TimePtr("null")
+	assert.NoError(t, err)
+	assert.Nil(t, tm)
+}
+
+func TestRowOr(t *testing.T) {
+	r := Row{"null": nil, "int": int64(7), "bool": true, "bad": "x"}
+
+	i, err := r.IntOr("null", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, i)
+
+	i, err = r.IntOr("int", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, i)
+
+	// the default is only for NULL, not for values that cannot be converted
+	i, err = r.IntOr("bad", 3)
+	assert.Equal(t, ErrCannotConvert, err)
+	assert.Equal(t, 0, i)
+
+	b, err := r.BoolOr("null", true)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	s, err := r.StringOr("null", "none")
+	assert.NoError(t, err)
+	assert.Equal(t, "none", s)
+}
+
+func TestRowNullableAccessors(t *testing.T) {
+	r := Row{"null": nil, "n": int64(7), "raw": "x", "at": []byte("2017-01-02 03:04:05"), "took": []byte("00:01:30")}
+
+	i64, err := r.Int64Ptr("null")
+	assert.NoError(t, err)
+	assert.Nil(t, i64)
+
+	i64, err = r.Int64Ptr("n")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), *i64)
+
+	i32, err := r.Int32Or("null", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), i32)
+
+	i32p, err := r.Int32Ptr("n")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), *i32p)
+
+	u64, err := r.Uint64Or("n", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), u64)
+
+	u64p, err := r.Uint64Ptr("null")
+	assert.NoError(t, err)
+	assert.Nil(t, u64p)
+
+	i64, err = r.Int64Ptr("raw")
+	assert.Equal(t, ErrCannotConvert, err)
+	assert.Nil(t, i64)
+
+	b, err := r.BytesPtr("raw")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("x"), *b)
+
+	b, err = r.BytesPtr("null")
+	assert.NoError(t, err)
+	assert.Nil(t, b)
+
+	d, err := r.DatePtr("at")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC), *d)
+
+	day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dv, err := r.DateOr("null", day)
+	assert.NoError(t, err)
+	assert.Equal(t, day, dv)
+
+	dur, err := r.DurationPtr("took")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, *dur)
+
+	durv, err := r.DurationOr("null", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, durv)
+}
@@ -0,0 +1,122 @@
+package gdo
+
+import "time"
+
+// Option configures a GDO created with New
+type Option func(*config)
+
+type config struct {
+	dialect       Dialect
+	emptySlice    EmptySlice
+	retryPolicy   RetryPolicy
+	interceptors  []Interceptor
+	redact        []string
+	metrics       *Metrics
+	tracer        Tracer
+	mapper        *nameMapper
+	strictMapping bool
+	location      *time.Location
+
+	// txID is set on the copy of the config carried by a Transaction
+	txID uint64
+}
+
+// WithDialect sets the Dialect used to rewrite and interpolate statements.
+// The default is MySQL.
+func WithDialect(d Dialect) Option {
+	return func(c *config) {
+		c.dialect = d
+	}
+}
+
+// WithEmptySlice sets what a named parameter bound to an empty slice expands
+// to.  The default is EmptySliceError.
+func WithEmptySlice(e EmptySlice) Option {
+	return func(c *config) {
+		c.emptySlice = e
+	}
+}
+
+// WithLocation sets the time zone of times without a zone read through the
+// GDO.  The default is UTC.  The text of a DATE, DATETIME or TIMESTAMP column
+// is read as a time.Time in loc as rows are scanned, so that Row.Time and
+// Row.Date return it, and MetaRow.Time and MetaRow.Date read Unix times and
+// other text in loc too.
+func WithLocation(loc *time.Location) Option {
+	return func(c *config) {
+		c.location = loc
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// getDialect is safe to call on a nil config, which is what a GDO or
+// Transaction built without New carries
+func (c *config) getDialect() Dialect {
+	if c == nil || c.dialect == nil {
+		return MySQL
+	}
+
+	return c.dialect
+}
+
+func (c *config) getEmptySlice() EmptySlice {
+	if c == nil {
+		return EmptySliceError
+	}
+
+	return c.emptySlice
+}
+
+func (c *config) getRetryPolicy() RetryPolicy {
+	if c == nil {
+		return RetryPolicy{}
+	}
+
+	return c.retryPolicy
+}
+
+func (c *config) getLocation() *time.Location {
+	if c == nil || c.location == nil {
+		return time.UTC
+	}
+
+	return c.location
+}
+
+func (c *config) getInterceptors() []Interceptor {
+	if c == nil {
+		return nil
+	}
+
+	return c.interceptors
+}
+
+// withTxID returns a copy of c for the transaction numbered id
+func (c *config) withTxID(id uint64) *config {
+	var tc config
+
+	if c != nil {
+		tc = *c
+	}
+
+	tc.txID = id
+
+	return &tc
+}
+
+func (c *config) getTxID() uint64 {
+	if c == nil {
+		return 0
+	}
+
+	return c.txID
+}
@@ -1,12 +1,22 @@
 package gdo
 
 import (
+	"container/list"
 	"context"
 	"database/sql"
+	"sync"
 )
 
-type queryCtxPreparedFunc func(context.Context, ...interface{}) (*sql.Rows, error)
-type execCtxPreparedFunc func(context.Context, ...interface{}) (sql.Result, error)
+// maxArities bounds how many slice-expanded variants of one prepared
+// statement are kept open
+const maxArities = 32
+
+type queryCtxPreparedFunc func(*sql.Stmt, context.Context, ...interface{}) (*sql.Rows, error)
+type execCtxPreparedFunc func(*sql.Stmt, context.Context, ...interface{}) (sql.Result, error)
+
+type preparer interface {
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+}
 
 type queryNamedArgs struct {
 	dict  map[string][]int
@@ -17,6 +27,49 @@ type PreparedStatement struct {
 	*Statement
 	*sql.Stmt
 	queryNamedArgs queryNamedArgs
+	cfg            *config
+
+	// preparer prepares the query again, from the template of Statement,
+	// when a slice parameter changes the number of placeholders
+	preparer preparer
+	arities  *stmtCache
+	// release is set on a copy executed with a statement from arities, and
+	// gives the statement back once it has been executed
+	release func()
+}
+
+// stmtCache holds the statements prepared for each arity of the slice
+// parameters of a PreparedStatement, keyed by the rendered query.  It keeps
+// the maxArities most recently used, and a statement evicted while being
+// executed is closed once the last execution using it is done.
+type stmtCache struct {
+	mu    sync.Mutex
+	stmts map[string]*list.Element
+	// lru holds a *cachedStmt for each of stmts, most recently used first
+	lru list.List
+}
+
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// Close closes the statement along with any variants prepared for slice
+// parameters
+func (ps *PreparedStatement) Close() error {
+	err := ps.arities.close()
+
+	if ps.Stmt == nil {
+		return err
+	}
+
+	if cerr := ps.Stmt.Close(); cerr != nil {
+		return cerr
+	}
+
+	return err
 }
 
 func (ps *PreparedStatement) Exec() (ExecResult, error) {
@@ -24,7 +77,7 @@ func (ps *PreparedStatement) Exec() (ExecResult, error) {
 }
 
 func (ps *PreparedStatement) ExecContext(ctx context.Context) (ExecResult, error) {
-	return doPreparedExecCtx(ctx, ps.Stmt.ExecContext, ps)
+	return doPreparedExecCtx(ctx, (*sql.Stmt).ExecContext, ps)
 }
 
 func (ps *PreparedStatement) Query() (QueryResult, error) {
@@ -32,7 +85,7 @@ func (ps *PreparedStatement) Query() (QueryResult, error) {
 }
 
 func (ps *PreparedStatement) QueryContext(ctx context.Context) (QueryResult, error) {
-	return doPreparedQueryCtx(ctx, ps.Stmt.QueryContext, ps)
+	return doPreparedQueryCtx(ctx, (*sql.Stmt).QueryContext, ps)
 }
 
 func (ps *PreparedStatement) QueryRow() QueryRowResult {
@@ -40,7 +93,66 @@ func (ps *PreparedStatement) QueryRow() QueryRowResult {
 }
 
 func (ps *PreparedStatement) QueryRowContext(ctx context.Context) QueryRowResult {
-	return doPreparedQueryRowCtx(ctx, ps.Stmt.QueryContext, ps)
+	return doPreparedQueryRowCtx(ctx, (*sql.Stmt).QueryContext, ps)
+}
+
+// ExecArgs executes the statement with args bound for this call only, leaving
+// the arguments bound to ps untouched, so it is safe to call from several
+// goroutines at once.  Each arg is a sql.NamedArg, a []sql.NamedArg, a
+// map[string]interface{} of named values or else a positional value.
+func (ps *PreparedStatement) ExecArgs(ctx context.Context, args ...interface{}) (ExecResult, error) {
+	return doPreparedExecCtx(ctx, (*sql.Stmt).ExecContext, ps.withArgs(args))
+}
+
+// QueryArgs is the query counterpart of ExecArgs
+func (ps *PreparedStatement) QueryArgs(ctx context.Context, args ...interface{}) (QueryResult, error) {
+	return doPreparedQueryCtx(ctx, (*sql.Stmt).QueryContext, ps.withArgs(args))
+}
+
+// QueryRowArgs is the single row counterpart of ExecArgs
+func (ps *PreparedStatement) QueryRowArgs(ctx context.Context, args ...interface{}) QueryRowResult {
+	return doPreparedQueryRowCtx(ctx, (*sql.Stmt).QueryContext, ps.withArgs(args))
+}
+
+// withArgs returns a copy of ps bound to args alone.  The copy shares the
+// prepared statements of ps but none of its bound arguments.
+func (ps *PreparedStatement) withArgs(args []interface{}) *PreparedStatement {
+	s := &Statement{
+		query:           ps.query,
+		isParameterized: ps.isParameterized,
+		tmpl:            ps.tmpl,
+	}
+
+	for _, arg := range args {
+		switch arg.(type) {
+		case sql.NamedArg:
+			s.namedArgs = append(s.namedArgs, arg.(sql.NamedArg))
+		case []sql.NamedArg:
+			s.namedArgs = append(s.namedArgs, arg.([]sql.NamedArg)...)
+		case map[string]interface{}:
+			for name, value := range arg.(map[string]interface{}) {
+				s.namedArgs = append(s.namedArgs, sql.Named(name, value))
+			}
+		default:
+			s.args = append(s.args, arg)
+		}
+	}
+
+	return &PreparedStatement{
+		Statement:      s,
+		Stmt:           ps.Stmt,
+		queryNamedArgs: ps.queryNamedArgs,
+		cfg:            ps.cfg,
+		preparer:       ps.preparer,
+		arities:        ps.arities,
+	}
+}
+
+// done releases the statement ps was executed with
+func (ps *PreparedStatement) done() {
+	if ps.release != nil {
+		ps.release()
+	}
 }
 
 func doPreparedQueryCtx(ctx context.Context, fn queryCtxPreparedFunc, ps *PreparedStatement) (QueryResult, error) {
@@ -48,14 +160,23 @@ func doPreparedQueryCtx(ctx context.Context, fn queryCtxPreparedFunc, ps *Prepar
 	var err error
 
 	if ps.isParameterized && len(ps.namedArgs) > 0 {
-		ps, err = processPreparedStatement(ps) // get args for query
+		ps, err = processPreparedStatement(ctx, ps) // get args for query
 
 		if err != nil {
 			return QueryResult{}, err
 		}
 	}
 
-	rows, err = fn(ctx, ps.args...)
+	defer ps.done()
+
+	e := &Execution{Op: OpQuery, Statement: ps.Statement, Prepared: true}
+
+	err = intercept(ctx, ps.cfg, e, func(ctx context.Context) error {
+		var qerr error
+		rows, qerr = fn(ps.Stmt, ctx, unwrapArgs(e.Statement.args)...)
+
+		return qerr
+	})
 
 	if err != nil {
 		return QueryResult{}, err
@@ -69,9 +190,11 @@ func doPreparedQueryCtx(ctx context.Context, fn queryCtxPreparedFunc, ps *Prepar
 
 	return QueryResult{
 		GDOResult: GDOResult{
-			executedStmt: ps.Statement,
+			executedStmt: e.Statement,
+			cfg:          ps.cfg,
 		},
 		Rows: rows, Cols: cols,
+		cursor: newCursor(ctx, ps.cfg, rows, cols),
 	}, nil
 }
 
@@ -90,14 +213,25 @@ func doPreparedExecCtx(ctx context.Context, fn execCtxPreparedFunc, ps *Prepared
 	var err error
 
 	if ps.isParameterized && len(ps.namedArgs) > 0 {
-		ps, err = processPreparedStatement(ps) // get args for query
+		ps, err = processPreparedStatement(ctx, ps) // get args for query
 
 		if err != nil {
 			return ExecResult{}, err
 		}
 	}
 
-	result, err = fn(ctx, ps.args...)
+	defer ps.done()
+
+	e := &Execution{Op: OpExec, Statement: ps.Statement, Prepared: true}
+
+	err = intercept(ctx, ps.cfg, e, func(ctx context.Context) error {
+		var eerr error
+		result, eerr = fn(ps.Stmt, ctx, unwrapArgs(e.Statement.args)...)
+
+		setRowsAffected(e, result)
+
+		return eerr
+	})
 
 	if err != nil {
 		return ExecResult{}, err
@@ -105,29 +239,191 @@ func doPreparedExecCtx(ctx context.Context, fn execCtxPreparedFunc, ps *Prepared
 
 	return ExecResult{
 		GDOResult: GDOResult{
-			executedStmt: ps.Statement,
+			executedStmt: e.Statement,
+			cfg:          ps.cfg,
 		},
 		Result: result,
 	}, nil
 }
 
-func processPreparedStatement(ps *PreparedStatement) (*PreparedStatement, error) {
+func processPreparedStatement(ctx context.Context, ps *PreparedStatement) (*PreparedStatement, error) {
+	if hasExpandable(ps.namedArgs) {
+		return processExpandedStatement(ctx, ps)
+	}
+
 	args := make([]interface{}, ps.queryNamedArgs.total)
 
 	for _, namedArg := range ps.namedArgs {
-		inds := ps.queryNamedArgs.dict[namedArg.Name]
+		inds, ok := ps.queryNamedArgs.dict[namedArg.Name]
+
+		if !ok {
+			return nil, ErrParameterMismatch
+		}
 
 		for _, k := range inds {
 			args[k] = namedArg.Value
 		}
 	}
 
+	for name := range ps.queryNamedArgs.dict {
+		if _, ok := findNamedArg(ps.namedArgs, name); !ok {
+			return nil, ErrParameterMismatch
+		}
+	}
+
 	return &PreparedStatement{
 		Stmt: ps.Stmt,
 		Statement: &Statement{
 			query:     ps.query,
 			namedArgs: ps.namedArgs,
 			args:      args,
+			tmpl:      ps.tmpl,
 		},
+		cfg: ps.cfg,
+	}, nil
+}
+
+// processExpandedStatement renders the source query with one placeholder per
+// slice element and runs it on a statement prepared for that arity
+func processExpandedStatement(ctx context.Context, ps *PreparedStatement) (*PreparedStatement, error) {
+	s, err := processStatment(ps.cfg, ps.Statement)
+
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, release, err := ps.arities.prepare(ctx, ps.preparer, s.query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedStatement{
+		Stmt:      stmt,
+		Statement: s,
+		cfg:       ps.cfg,
+		release:   release,
 	}, nil
 }
+
+// prepare returns the statement for query, preparing it on first use, along
+// with a func to call once it has been executed.  Until then it is not closed,
+// even if it is evicted.  The statement is prepared without holding c.mu, so
+// that other arities are not held up by a slow prepare.
+func (c *stmtCache) prepare(ctx context.Context, p preparer, query string) (*sql.Stmt, func(), error) {
+	if stmt, release, ok := c.lookup(query); ok {
+		return stmt, release, nil
+	}
+
+	stmt, err := p.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+
+	// another caller may have prepared query in the meantime
+	if el, ok := c.stmts[query]; ok {
+		c.lru.MoveToFront(el)
+
+		cs := el.Value.(*cachedStmt)
+		release := c.acquire(cs)
+
+		c.mu.Unlock()
+
+		stmt.Close()
+
+		return cs.stmt, release, nil
+	}
+
+	if c.stmts == nil {
+		c.stmts = make(map[string]*list.Element)
+	}
+
+	if c.lru.Len() >= maxArities {
+		c.evict(c.lru.Back())
+	}
+
+	cs := &cachedStmt{query: query, stmt: stmt}
+	c.stmts[query] = c.lru.PushFront(cs)
+
+	release := c.acquire(cs)
+
+	c.mu.Unlock()
+
+	return stmt, release, nil
+}
+
+// lookup returns the cached statement for query, if any, as prepare does
+func (c *stmtCache) lookup(query string) (*sql.Stmt, func(), bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.stmts[query]
+
+	if !ok {
+		return nil, nil, false
+	}
+
+	c.lru.MoveToFront(el)
+
+	cs := el.Value.(*cachedStmt)
+
+	return cs.stmt, c.acquire(cs), true
+}
+
+// acquire counts a use of cs, which the returned func ends.  It is called
+// with c.mu held.
+func (c *stmtCache) acquire(cs *cachedStmt) func() {
+	cs.refs++
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			cs.refs--
+
+			if cs.evicted && cs.refs == 0 {
+				cs.stmt.Close()
+			}
+		})
+	}
+}
+
+// evict drops the entry el from the cache, closing its statement unless it
+// is in use.  It is called with c.mu held.
+func (c *stmtCache) evict(el *list.Element) error {
+	cs := c.lru.Remove(el).(*cachedStmt)
+	delete(c.stmts, cs.query)
+
+	cs.evicted = true
+
+	if cs.refs > 0 {
+		return nil
+	}
+
+	return cs.stmt.Close()
+}
+
+func (c *stmtCache) close() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var err error
+
+	for c.lru.Len() > 0 {
+		if cerr := c.evict(c.lru.Back()); cerr != nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,286 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestPreparedStatementArgs(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	p := mock.ExpectPrepare(regexp.QuoteMeta("UPDATE Foo SET bar = ? WHERE id = ?"))
+	p.ExpectExec().WithArgs("a", 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.ExpectExec().WithArgs("b", 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	p.ExpectQuery().WithArgs("c", 3).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(3)))
+
+	ps, err := New(db).Prepare("UPDATE Foo SET bar = :bar: WHERE id = :id:")
+	assert.NoError(t, err)
+
+	ps.BindNamedArg(sql.Named("bar", "shared"))
+
+	r, err := ps.ExecArgs(context.Background(), sql.Named("bar", "a"), sql.Named("id", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE Foo SET bar = 'a' WHERE id = 1", r.LastExecutedQuery())
+
+	_, err = ps.ExecArgs(context.Background(), map[string]interface{}{"bar": "b", "id": 2})
+	assert.NoError(t, err)
+
+	row := ps.QueryRowArgs(context.Background(), []sql.NamedArg{sql.Named("bar", "c"), sql.Named("id", 3)})
+	assert.NoError(t, row.LastError())
+	assert.Equal(t, Row{"id": int64(3)}, row.FetchRow())
+
+	// arguments bound to the statement itself are left alone
+	assert.Equal(t, []sql.NamedArg{sql.Named("bar", "shared")}, ps.namedArgs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreparedStatementArgsPositional(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectPrepare(regexp.QuoteMeta("SELECT * FROM Foo WHERE id = ?")).
+		ExpectQuery().WithArgs(7).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(7)))
+
+	ps, err := New(db).Prepare("SELECT * FROM Foo WHERE id = ?")
+	assert.NoError(t, err)
+
+	r, err := ps.QueryArgs(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, Rows{Row{"id": int64(7)}}, r.FetchRows())
+	assert.Empty(t, ps.args)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreparedStatementArgsConcurrent(t *testing.T) {
+	const n = 20
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+	mock.MatchExpectationsInOrder(false)
+
+	p := mock.ExpectPrepare(regexp.QuoteMeta("UPDATE Foo SET bar = ? WHERE id = ?"))
+
+	mock.ExpectPrepare(regexp.QuoteMeta("UPDATE Foo SET bar = ? WHERE id IN (?)"))
+
+	expanded := mock.ExpectPrepare(regexp.QuoteMeta("UPDATE Foo SET bar = ? WHERE id IN (?, ?)"))
+
+	for i := 0; i < n; i++ {
+		p.ExpectExec().WithArgs(i, i).WillReturnResult(sqlmock.NewResult(0, 1))
+		expanded.ExpectExec().WithArgs(i, i, i+1).WillReturnResult(sqlmock.NewResult(0, 2))
+	}
+
+	g := New(db)
+
+	ps, err := g.Prepare("UPDATE Foo SET bar = :bar: WHERE id = :id:")
+	assert.NoError(t, err)
+
+	psIn, err := g.Prepare("UPDATE Foo SET bar = :bar: WHERE id IN (:ids:)")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			_, err := ps.ExecArgs(context.Background(), sql.Named("bar", i), sql.Named("id", i))
+			assert.NoError(t, err)
+
+			_, err = psIn.ExecArgs(context.Background(), sql.Named("bar", i), sql.Named("ids", []int{i, i + 1}))
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPreparedStatementArgsConcurrentArities(t *testing.T) {
+	const arities = maxArities + 8
+	const n = 4 * arities
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectPrepare(regexp.QuoteMeta("SELECT * FROM Foo WHERE id IN (?)"))
+
+	// evicted arities may be prepared again, and executions racing to
+	// prepare one may each do so, so there is a prepare for every execution
+	// of each, and one more for running it on another connection
+	for i := 0; i < n; i++ {
+		query := "SELECT * FROM Foo WHERE id IN (" + strings.Repeat("?, ", i%arities) + "?)"
+
+		mock.ExpectPrepare("^" + regexp.QuoteMeta(query) + "$")
+		mock.ExpectPrepare("^" + regexp.QuoteMeta(query) + "$")
+		mock.ExpectQuery("^" + regexp.QuoteMeta(query) + "$").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(i)))
+	}
+
+	ps, err := New(db).Prepare("SELECT * FROM Foo WHERE id IN (:ids:)")
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			r, err := ps.QueryArgs(context.Background(), sql.Named("ids", make([]int, i%arities+1)))
+
+			if assert.NoError(t, err) {
+				_, err = r.FetchRowsE()
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Len(t, ps.arities.stmts, maxArities)
+	assert.Equal(t, maxArities, ps.arities.lru.Len())
+	assert.NoError(t, ps.Close())
+	assert.Empty(t, ps.arities.stmts)
+}
+
+func TestStmtCacheEviction(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	for i := 0; i <= maxArities; i++ {
+		mock.ExpectPrepare(fmt.Sprintf("^SELECT %d$", i))
+	}
+
+	mock.ExpectExec("^SELECT 0$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectPrepare(fmt.Sprintf("^SELECT %d$", maxArities+1))
+
+	var c stmtCache
+
+	held, release, err := c.prepare(context.Background(), db, "SELECT 0")
+	assert.NoError(t, err)
+
+	for i := 1; i <= maxArities; i++ {
+		_, r, err := c.prepare(context.Background(), db, fmt.Sprintf("SELECT %d", i))
+		assert.NoError(t, err)
+		r()
+	}
+
+	// SELECT 0 was the least recently used, so it was evicted, but it stays
+	// open until released
+	assert.NotContains(t, c.stmts, "SELECT 0")
+
+	_, err = held.Exec()
+	assert.NoError(t, err)
+
+	release()
+	release()
+
+	_, err = held.Exec()
+	assert.EqualError(t, err, "sql: statement is closed")
+
+	// using a statement makes it the most recently used
+	_, r, err := c.prepare(context.Background(), db, "SELECT 1")
+	assert.NoError(t, err)
+	r()
+
+	_, r, err = c.prepare(context.Background(), db, fmt.Sprintf("SELECT %d", maxArities+1))
+	assert.NoError(t, err)
+	r()
+
+	assert.Contains(t, c.stmts, "SELECT 1")
+	assert.NotContains(t, c.stmts, "SELECT 2")
+	assert.Len(t, c.stmts, maxArities)
+
+	assert.NoError(t, c.close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// blockingPreparer prepares on db once unblock is closed, telling entered
+// when it starts
+type blockingPreparer struct {
+	db      *sql.DB
+	entered chan struct{}
+	unblock chan struct{}
+}
+
+func (p blockingPreparer) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	p.entered <- struct{}{}
+	<-p.unblock
+
+	return p.db.PrepareContext(ctx, query)
+}
+
+func TestStmtCachePrepareUnlocked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectPrepare("^SELECT 1$")
+	mock.ExpectPrepare("^SELECT 2$")
+	mock.ExpectPrepare("^SELECT 2$").WillBeClosed()
+	mock.ExpectExec("^SELECT 2$").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	var c stmtCache
+
+	_, r, err := c.prepare(context.Background(), db, "SELECT 1")
+	assert.NoError(t, err)
+	r()
+
+	p := blockingPreparer{db: db, entered: make(chan struct{}), unblock: make(chan struct{})}
+
+	stmts := make([]*sql.Stmt, 2)
+	releases := make([]func(), 2)
+
+	var wg sync.WaitGroup
+
+	for i := range stmts {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			var err error
+			stmts[i], releases[i], err = c.prepare(context.Background(), p, "SELECT 2")
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	<-p.entered
+	<-p.entered
+
+	// other arities are not held up while SELECT 2 is being prepared
+	_, r, err = c.prepare(context.Background(), db, "SELECT 1")
+	assert.NoError(t, err)
+	r()
+
+	close(p.unblock)
+	wg.Wait()
+
+	// the statement prepared second is closed and the first one shared
+	assert.Same(t, stmts[0], stmts[1])
+	assert.Len(t, c.stmts, 2)
+
+	_, err = stmts[0].Exec()
+	assert.NoError(t, err)
+
+	releases[0]()
+	releases[1]()
+
+	assert.NoError(t, c.close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
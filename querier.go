@@ -0,0 +1,30 @@
+package gdo
+
+import "context"
+
+// Querier runs statements.  It is implemented by GDO, Transaction and Conn, so
+// code taking a Querier works the same inside and outside a transaction.
+type Querier interface {
+	Exec(s *Statement) (ExecResult, error)
+	ExecContext(ctx context.Context, s *Statement) (ExecResult, error)
+	Query(s *Statement) (QueryResult, error)
+	QueryContext(ctx context.Context, s *Statement) (QueryResult, error)
+	QueryRow(s *Statement) QueryRowResult
+	QueryRowContext(ctx context.Context, s *Statement) QueryRowResult
+}
+
+// Preparer prepares statements.  It is implemented by GDO, Transaction and
+// Conn.
+type Preparer interface {
+	Prepare(query string) (*PreparedStatement, error)
+	PrepareContext(ctx context.Context, query string) (*PreparedStatement, error)
+}
+
+var (
+	_ Querier  = GDO{}
+	_ Querier  = Transaction{}
+	_ Querier  = Conn{}
+	_ Preparer = GDO{}
+	_ Preparer = Transaction{}
+	_ Preparer = Conn{}
+)
@@ -0,0 +1,105 @@
+package gdo
+
+import (
+	"database/sql"
+	"log/slog"
+	"strings"
+)
+
+// redacted stands in for a sensitive value in LastExecutedQuery and logs
+const redacted = "[REDACTED]"
+
+type sensitive struct {
+	v interface{}
+}
+
+// Sensitive marks v as a value that must never be logged.  It is passed to the
+// driver as v but shown as [REDACTED] by LastExecutedQuery and in logs.  The
+// elements of a sensitive slice are each sensitive.
+func Sensitive(v interface{}) interface{} {
+	return sensitive{v}
+}
+
+func (s sensitive) String() string {
+	return redacted
+}
+
+func (s sensitive) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// WithRedact redacts the values of named parameters whose name contains any of
+// names, ignoring case, as if they had been bound with Sensitive
+func WithRedact(names ...string) Option {
+	return func(c *config) {
+		for _, name := range names {
+			c.redact = append(c.redact, strings.ToLower(name))
+		}
+	}
+}
+
+func (c *config) isRedacted(name string) bool {
+	if c == nil || len(c.redact) == 0 {
+		return false
+	}
+
+	name = strings.ToLower(name)
+
+	for _, r := range c.redact {
+		if strings.Contains(name, r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactNamedArgs returns namedArgs with the values of redacted names wrapped
+// in Sensitive
+func (c *config) redactNamedArgs(namedArgs []sql.NamedArg) []sql.NamedArg {
+	var out []sql.NamedArg
+
+	for i, arg := range namedArgs {
+		if !c.isRedacted(arg.Name) {
+			continue
+		}
+
+		if out == nil {
+			out = append([]sql.NamedArg(nil), namedArgs...)
+		}
+
+		out[i].Value = Sensitive(arg.Value)
+	}
+
+	if out == nil {
+		return namedArgs
+	}
+
+	return out
+}
+
+// unwrapArgs returns args with any Sensitive values replaced by what they
+// wrap, which is what the driver is given
+func unwrapArgs(args []interface{}) []interface{} {
+	var out []interface{}
+
+	for i, arg := range args {
+		s, ok := arg.(sensitive)
+
+		if !ok {
+			continue
+		}
+
+		if out == nil {
+			out = append([]interface{}(nil), args...)
+		}
+
+		out[i] = s.v
+	}
+
+	if out == nil {
+		return args
+	}
+
+	return out
+}
@@ -3,11 +3,11 @@ package gdo
 import (
 	"database/sql"
 	"reflect"
-	"strings"
 )
 
 type GDOResult struct {
 	executedStmt *Statement
+	cfg          *config
 }
 
 type ExecResult struct {
@@ -19,6 +19,8 @@ type QueryResult struct {
 	GDOResult
 	Rows *sql.Rows
 	Cols []string
+
+	cursor *cursor
 }
 
 type QueryRowResult struct {
@@ -26,81 +28,116 @@ type QueryRowResult struct {
 	err error
 }
 
+// FetchRows reads every row and closes the result.  It stops at the first
+// error without reporting it; FetchRowsE returns it.
 func (r QueryResult) FetchRows() Rows {
-	var m Rows
+	m, _ := r.FetchRowsE()
+
+	return m
+}
 
-	results := make([]interface{}, len(r.Cols))
-	rawResults := make([]interface{}, len(r.Cols))
+// FetchRowsE reads every row and closes the result, returning the rows read
+// before any error from scanning, iterating or closing
+func (r QueryResult) FetchRowsE() (Rows, error) {
+	var m Rows
 
-	for i := range rawResults {
-		rawResults[i] = &results[i]
+	if r.Rows == nil {
+		return m, ErrNoCursor
 	}
 
-	defer r.Rows.Close()
+	s := newRowScanner(r.Cols)
 
 	for r.Rows.Next() {
-		assoc := make(map[string]interface{})
+		assoc, err := s.scan(r.Rows)
 
-		r.Rows.Scan(rawResults...)
-
-		for i := range results {
-			assoc[r.Cols[i]] = results[i]
+		if err != nil {
+			r.Rows.Close()
+			return m, err
 		}
 
 		m = append(m, assoc)
 	}
 
-	return m
+	return m, closeRows(r.Rows)
 }
 
+// FetchRow reads the first row and closes the result.  It returns nil when
+// there is no row or on any error; FetchRowE tells these apart.
 func (qrr QueryRowResult) FetchRow() Row {
-	var r Row
+	r, _ := qrr.FetchRowE()
+
+	return r
+}
+
+// FetchRowE reads the first row and closes the result.  It returns the error
+// of the query, or of reading the row, and sql.ErrNoRows when there is none.
+func (qrr QueryRowResult) FetchRowE() (Row, error) {
+	if qrr.err != nil {
+		return nil, qrr.err
+	}
 
 	if qrr.Rows == nil {
-		return r
+		return nil, sql.ErrNoRows
+	}
+
+	defer qrr.Rows.Close()
+
+	if !qrr.Rows.Next() {
+		if err := closeRows(qrr.Rows); err != nil {
+			return nil, err
+		}
+
+		return nil, sql.ErrNoRows
 	}
 
-	rs := qrr.FetchRows()
+	r, err := newRowScanner(qrr.Cols).scan(qrr.Rows)
 
-	if len(rs) < 1 {
-		return r
+	if err != nil {
+		return nil, err
 	}
 
-	return rs[0]
+	return r, closeRows(qrr.Rows)
 }
 
+// closeRows closes rows that have been read, returning any error that ended
+// the iteration early or else the error from closing them
+func closeRows(rows *sql.Rows) error {
+	err := rows.Err()
+
+	if cerr := rows.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// FetchRowsTyped reads every row into a slice of the struct type t points
+// to, returned as an interface{} holding a []T.  Each column is scanned into
+// the field it maps to by name: its gdo tag, or else the field name ignoring
+// case or as set with WithNameMapper.  Fields of embedded structs are
+// promoted, fields of other nested structs are prefixed with the name of the
+// struct field and a dot, and fields tagged gdo:"-" or unexported are
+// skipped.  Columns without a field are dropped unless WithStrictMapping is
+// set.
 func (qr QueryResult) FetchRowsTyped(t interface{}) (interface{}, error) {
 	stype := reflect.TypeOf(t).Elem()
 
 	slice := newTypedSlice(stype)
 
-	newStruct := newTypedStruct(stype)
+	defer qr.Rows.Close()
 
-	cols, err := qr.Rows.Columns()
+	fields, err := qr.cfg.getMapper().mappingFor(stype).plan(qr.Cols, qr.cfg.getStrictMapping())
 
 	if err != nil {
 		return nil, err
 	}
 
-	ptrs := make([]interface{}, len(cols))
-
-	fieldsLen := newStruct.NumField()
-	for i := 0; i < len(cols); i++ {
-		ptrs[i] = new(interface{})
-
-		if i < fieldsLen {
-			field := newStruct.Field(i)
-
-			if field.CanAddr() && isValidField(stype.Field(i), cols[i]) {
-				ptrs[i] = field.Addr().Interface()
-			}
-		}
-	}
-
-	defer qr.Rows.Close()
+	sink := make([]interface{}, len(fields))
 
 	for qr.Rows.Next() {
-		err := qr.Rows.Scan(ptrs...)
+		newStruct := newTypedStruct(stype)
+
+		err := qr.Rows.Scan(scanDest(newStruct, fields, sink)...)
 
 		if err != nil {
 			return nil, err
@@ -109,6 +146,10 @@ func (qr QueryResult) FetchRowsTyped(t interface{}) (interface{}, error) {
 		slice.Set(reflect.Append(slice, newStruct))
 	}
 
+	if err := qr.Rows.Err(); err != nil {
+		return nil, err
+	}
+
 	return slice.Interface(), nil
 }
 
@@ -117,7 +158,7 @@ func (qrr QueryRowResult) LastError() error {
 }
 
 func (r GDOResult) LastExecutedQuery() string {
-	return r.executedStmt.lastExecutedQuery()
+	return r.executedStmt.lastExecutedQuery(r.cfg)
 }
 
 // HELPERS
@@ -133,10 +174,3 @@ func newTypedSlice(t reflect.Type) reflect.Value {
 func newTypedStruct(t reflect.Type) reflect.Value {
 	return reflect.New(t).Elem()
 }
-
-func isValidField(field reflect.StructField, column string) bool {
-	validName := field.Name == strings.Title(column)
-	validTag := field.Tag.Get("gdo") == column
-
-	return validName || validTag
-}
@@ -0,0 +1,87 @@
+package gdo
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestFetchRowsE(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).
+		AddRow(int64(1)).
+		AddRow(int64(2)).
+		RowError(1, fail))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).
+		AddRow(int64(1)).
+		RowError(0, fail))
+
+	g := New(db)
+
+	r, err := g.Query(NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	rows, err := r.FetchRowsE()
+	assert.NoError(t, err)
+	assert.Equal(t, Rows{{"id": int64(1)}, {"id": int64(2)}}, rows)
+
+	// rows read before the error are returned with it
+	r, err = g.Query(NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	rows, err = r.FetchRowsE()
+	assert.Equal(t, fail, err)
+	assert.Equal(t, Rows{{"id": int64(1)}}, rows)
+
+	r, err = g.Query(NewStatement("SELECT id FROM Foo"))
+	assert.NoError(t, err)
+
+	assert.Empty(t, r.FetchRows())
+
+	_, err = QueryResult{}.FetchRowsE()
+	assert.Equal(t, ErrNoCursor, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchRowE(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).RowError(0, fail))
+	mock.ExpectQuery("SELECT").WillReturnError(fail)
+
+	g := New(db)
+
+	row, err := g.QueryRow(NewStatement("SELECT id FROM Foo")).FetchRowE()
+	assert.NoError(t, err)
+	assert.Equal(t, Row{"id": int64(1)}, row)
+
+	row, err = g.QueryRow(NewStatement("SELECT id FROM Foo")).FetchRowE()
+	assert.Equal(t, sql.ErrNoRows, err)
+	assert.Nil(t, row)
+
+	// a broken result is not mistaken for an empty one
+	_, err = g.QueryRow(NewStatement("SELECT id FROM Foo")).FetchRowE()
+	assert.Equal(t, fail, err)
+
+	qrr := g.QueryRow(NewStatement("SELECT id FROM Foo"))
+
+	_, err = qrr.FetchRowE()
+	assert.Equal(t, fail, err)
+	assert.Nil(t, qrr.FetchRow())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
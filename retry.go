@@ -0,0 +1,116 @@
+package gdo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides whether GDO.WithTx runs its function again, in a new
+// transaction, after an attempt fails
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first.  Zero or one
+	// means never retry.
+	MaxAttempts int
+	// Backoff returns how long to wait before the given attempt, starting at
+	// 2.  A nil Backoff retries straight away.
+	Backoff func(attempt int) time.Duration
+	// Retryable reports whether a failed attempt may be retried.  A nil
+	// Retryable uses IsRetryable.
+	Retryable func(err error) bool
+}
+
+// WithRetryPolicy sets the RetryPolicy used by GDO.WithTx.  By default a
+// transaction is attempted once.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *config) {
+		c.retryPolicy = p
+	}
+}
+
+// ExponentialBackoff returns a RetryPolicy.Backoff that waits base before the
+// second attempt and doubles the wait for each attempt after that, up to max
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+
+		for i := 2; i < attempt && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			return max
+		}
+
+		return d
+	}
+}
+
+// sqlStater is implemented by the errors of pgx (pgconn.PgError) and lib/pq
+type sqlStater interface {
+	SQLState() string
+}
+
+// sqlErrorNumberer is implemented by the errors of go-mssqldb
+type sqlErrorNumberer interface {
+	SQLErrorNumber() int32
+}
+
+// IsRetryable reports whether err is a deadlock or serialization failure,
+// after which the whole transaction can be run again
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var s sqlStater
+	if errors.As(err, &s) {
+		switch s.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+
+	var n sqlErrorNumberer
+	if errors.As(err, &n) && n.SQLErrorNumber() == 1205 {
+		return true
+	}
+
+	msg := err.Error()
+
+	// go-sql-driver/mysql and the sqlite drivers only expose these as text
+	for _, s := range []string{"Error 1213", "Error 1205", "database is locked", "database table is locked"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return IsRetryable(err)
+	}
+
+	return p.Retryable(err)
+}
+
+// wait sleeps before the given attempt, returning early with the error of ctx
+// if it is done first
+func (p RetryPolicy) wait(ctx context.Context, attempt int) error {
+	if p.Backoff == nil {
+		return ctx.Err()
+	}
+
+	t := time.NewTimer(p.Backoff(attempt))
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package gdo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	cases := []map[string]interface{}{
+		map[string]interface{}{"err": nil, "expected": false},
+		map[string]interface{}{"err": errors.New("gdo: something else"), "expected": false},
+		map[string]interface{}{"err": sqlStateError("40001"), "expected": true},
+		map[string]interface{}{"err": fmt.Errorf("wrapped: %w", sqlStateError("40P01")), "expected": true},
+		map[string]interface{}{"err": sqlStateError("23505"), "expected": false},
+		map[string]interface{}{"err": errors.New("Error 1213 (40001): Deadlock found when trying to get lock"), "expected": true},
+		map[string]interface{}{"err": errors.New("database is locked"), "expected": true},
+	}
+
+	for _, c := range cases {
+		err, _ := c["err"].(error)
+
+		assert.Equal(t, c["expected"], IsRetryable(err), fmt.Sprint(err))
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	assert.Equal(t, 10*time.Millisecond, backoff(2))
+	assert.Equal(t, 20*time.Millisecond, backoff(3))
+	assert.Equal(t, 40*time.Millisecond, backoff(4))
+	assert.Equal(t, 50*time.Millisecond, backoff(5))
+	assert.Equal(t, 50*time.Millisecond, backoff(10))
+}
@@ -1,232 +1,91 @@
 package gdo
 
 import (
-	"encoding/binary"
 	"errors"
-	"math"
-	"strconv"
+	"fmt"
 	"time"
 )
 
 var ErrColNotFound = errors.New("gdo: column not found")
 var ErrCannotConvert = errors.New("gdo: cannot convert value to type")
 
+// ErrNull is returned by the accessors of Row for a NULL value.  It wraps
+// ErrCannotConvert, which they returned for NULL before.
+var ErrNull = fmt.Errorf("%w: value is NULL", ErrCannotConvert)
+
 type Rows []Row
 type Row map[string]interface{}
 
+// Int converts the value of col as a lenient Converter does
 func (r Row) Int(col string) (int, error) {
-	val, ok := r[col]
+	return convertCol(r, col, lenient.Int)
+}
 
-	if !ok {
-		return 0, ErrColNotFound
-	}
+// Int64 converts the value of col as a lenient Converter does
+func (r Row) Int64(col string) (int64, error) {
+	return convertCol(r, col, lenient.Int64)
+}
 
-	var v int
-
-	switch val.(type) {
-	case int8:
-		v = int(val.(int8))
-	case int16:
-		v = int(val.(int16))
-	case int32:
-		v = int(val.(int32))
-	case int64:
-		v = int(val.(int64))
-	case float32:
-		v = int(val.(float32))
-	case float64:
-		v = int(val.(float64))
-	case []byte:
-		s, err := strconv.Atoi(string(val.([]byte)))
-
-		v = s
-
-		if err != nil {
-			return 0, ErrCannotConvert
-		}
-	default:
-		return 0, ErrCannotConvert
-	}
+// Int32 converts the value of col as a lenient Converter does
+func (r Row) Int32(col string) (int32, error) {
+	return convertCol(r, col, lenient.Int32)
+}
 
-	return v, nil
+// Uint64 converts the value of col as a lenient Converter does
+func (r Row) Uint64(col string) (uint64, error) {
+	return convertCol(r, col, lenient.Uint64)
 }
 
+// String converts the value of col as a lenient Converter does
 func (r Row) String(col string) (string, error) {
-	val, ok := r[col]
-
-	if !ok {
-		return "", ErrColNotFound
-	}
-
-	var v string
-
-	switch val.(type) {
-	case int8:
-		v = string(val.(int8))
-	case int16:
-		v = string(val.(int16))
-	case int32:
-		v = string(val.(int32))
-	case int64:
-		v = string(val.(int64))
-	case float32:
-		v = strconv.FormatFloat(float64(val.(float32)), 'f', -1, 32)
-	case float64:
-		v = strconv.FormatFloat(val.(float64), 'f', -1, 64)
-	case []byte:
-		v = string(val.([]byte))
-	default:
-		return "", ErrCannotConvert
-	}
-
-	return v, nil
+	return convertCol(r, col, lenient.String)
 }
 
+// Float64 converts the value of col as a lenient Converter does
 func (r Row) Float64(col string) (float64, error) {
-	val, ok := r[col]
-
-	if !ok {
-		return 0, ErrColNotFound
-	}
-
-	var v float64
-
-	switch val.(type) {
-	case int8:
-		v = float64(val.(int8))
-	case int16:
-		v = float64(val.(int16))
-	case int32:
-		v = float64(val.(int32))
-	case int64:
-		v = float64(val.(int64))
-	case float32:
-		v = float64(val.(float32))
-	case float64:
-		v = val.(float64)
-	case []byte:
-		v = math.Float64frombits(binary.LittleEndian.Uint64(val.([]byte)))
-	default:
-		return 0, ErrCannotConvert
-	}
-
-	return v, nil
+	return convertCol(r, col, lenient.Float64)
 }
 
+// Float32 converts the value of col as a lenient Converter does
 func (r Row) Float32(col string) (float32, error) {
-	val, ok := r[col]
-
-	if !ok {
-		return 0, ErrColNotFound
-	}
-
-	var v float32
-
-	switch val.(type) {
-	case int8:
-		v = float32(val.(int8))
-	case int16:
-		v = float32(val.(int16))
-	case int32:
-		v = float32(val.(int32))
-	case int64:
-		v = float32(val.(int64))
-	case float32:
-		v = val.(float32)
-	case float64:
-		v = float32(val.(float64))
-	case []byte:
-		v = math.Float32frombits(binary.LittleEndian.Uint32(val.([]byte)))
-	default:
-		return 0, ErrCannotConvert
-	}
-
-	return v, nil
+	return convertCol(r, col, lenient.Float32)
 }
 
+// Bool converts the value of col as a lenient Converter does
 func (r Row) Bool(col string) (bool, error) {
-	val, ok := r[col]
-
-	if !ok {
-		return false, ErrColNotFound
-	}
-
-	var v bool
-
-	switch val.(type) {
-	case int8:
-		v = val.(int8) != 0
-	case int16:
-		v = val.(int16) != 0
-	case int32:
-		v = val.(int32) != 0
-	case int64:
-		v = val.(int64) != 0
-	case float32:
-		v = val.(float32) != 0
-	case float64:
-		v = val.(float64) != 0
-	case []byte:
-		v = val.([]byte)[0] != 0
-	default:
-		return false, ErrCannotConvert
-	}
-
-	return v, nil
+	return convertCol(r, col, lenient.Bool)
 }
 
+// Bytes converts the value of col as a lenient Converter does
 func (r Row) Bytes(col string) ([]byte, error) {
-	val, ok := r[col]
-
-	if !ok {
-		return nil, ErrColNotFound
-	}
-
-	var v []byte
-
-	switch val.(type) {
-	case []byte:
-		v = val.([]byte)
-	default:
-		return nil, ErrCannotConvert
-	}
-
-	return v, nil
+	return convertCol(r, col, lenient.Bytes)
 }
 
+// Time converts the value of col as a lenient Converter does.  Rows read
+// through a GDO with WithLocation hold the times of their date and time
+// columns in that zone; other text without a zone is read as UTC.
 func (r Row) Time(col string) (time.Time, error) {
-	val, ok := r[col]
-
-	if !ok {
-		return time.Time{}, ErrColNotFound
-	}
-
-	var v time.Time
-	var err error
-
-	switch val.(type) {
-	case []byte:
-		vv := string(val.([]byte))
-
-		v, err = time.Parse(getTimeFormat(vv), vv)
+	return convertCol(r, col, lenient.Time)
+}
 
-		if err != nil {
-			return v, err
-		}
-	default:
-		return time.Time{}, ErrCannotConvert
-	}
+// Date converts the value of col as a lenient Converter does
+func (r Row) Date(col string) (time.Time, error) {
+	return convertCol(r, col, lenient.Date)
+}
 
-	return v, nil
+// Duration converts the value of col as a lenient Converter does
+func (r Row) Duration(col string) (time.Duration, error) {
+	return convertCol(r, col, lenient.Duration)
 }
 
-func getTimeFormat(s string) string {
-	// mysql format using go specific numbers
-	format := "2006-01-02 15:04:05.999999"
+// convertCol converts the value of col with conv
+func convertCol[T any](r Row, col string, conv func(interface{}) (T, error)) (T, error) {
+	val, ok := r[col]
 
-	if len(s) > len(format) {
-		return ""
+	if !ok {
+		var zero T
+		return zero, ErrColNotFound
 	}
 
-	return format[:len(s)]
+	return conv(val)
 }
@@ -0,0 +1,98 @@
+package gdo
+
+import (
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+// savepointSeq numbers the savepoints created by Transaction.WithTx
+var savepointSeq uint64
+
+// Savepoint creates a savepoint called name within the transaction
+func (tx Transaction) Savepoint(name string) error {
+	return tx.execSavepoint(savepointStatements(tx.cfg.getDialect()).Savepoint, name)
+}
+
+// RollbackTo undoes everything done in the transaction since the savepoint
+// called name was created, leaving the savepoint in place
+func (tx Transaction) RollbackTo(name string) error {
+	return tx.execSavepoint(savepointStatements(tx.cfg.getDialect()).RollbackToSavepoint, name)
+}
+
+// Release discards the savepoint called name, keeping what was done since it
+// was created
+func (tx Transaction) Release(name string) error {
+	return tx.execSavepoint(savepointStatements(tx.cfg.getDialect()).ReleaseSavepoint, name)
+}
+
+// WithTx runs fn as a nested transaction on a savepoint of tx.  When fn
+// returns an error or panics only its own work is rolled back, leaving tx
+// usable, and the error is returned or the panic resumed.  When the rollback
+// fails too, both errors are returned.
+func (tx Transaction) WithTx(fn func(tx Transaction) error) error {
+	name := "gdo_sp_" + strconv.FormatUint(atomic.AddUint64(&savepointSeq, 1), 10)
+
+	if err := tx.Savepoint(name); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			if tx.RollbackTo(name) == nil {
+				tx.Release(name)
+			}
+
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rerr := tx.RollbackTo(name); rerr != nil {
+			return errors.Join(err, rerr)
+		}
+
+		tx.Release(name)
+
+		return err
+	}
+
+	return tx.Release(name)
+}
+
+// execSavepoint runs a savepoint statement like any other statement of tx,
+// under the context tx was begun with
+func (tx Transaction) execSavepoint(statement func(string) string, name string) error {
+	query := statement(tx.cfg.getDialect().QuoteIdent(name))
+
+	if query == "" {
+		return nil
+	}
+
+	_, err := doExecCtx(tx.Tx.ExecContext, tx.context(), tx.cfg, NewStatement(query))
+
+	return err
+}
+
+// standardSavepoints spells savepoints the way the SQL standard does
+type standardSavepoints struct{}
+
+func (standardSavepoints) Savepoint(name string) string {
+	return "SAVEPOINT " + name
+}
+
+func (standardSavepoints) RollbackToSavepoint(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + name
+}
+
+func (standardSavepoints) ReleaseSavepoint(name string) string {
+	return "RELEASE SAVEPOINT " + name
+}
+
+func savepointStatements(d Dialect) SavepointDialect {
+	if sd, ok := d.(SavepointDialect); ok {
+		return sd
+	}
+
+	return standardSavepoints{}
+}
@@ -0,0 +1,164 @@
+package gdo
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSavepoints(t *testing.T) {
+	cases := []map[string]interface{}{
+		map[string]interface{}{
+			"dialect":    Postgres,
+			"savepoint":  `SAVEPOINT "sp1"`,
+			"rollbackTo": `ROLLBACK TO SAVEPOINT "sp1"`,
+			"release":    `RELEASE SAVEPOINT "sp1"`,
+		},
+		map[string]interface{}{
+			"dialect":    MySQL,
+			"savepoint":  "SAVEPOINT `sp1`",
+			"rollbackTo": "ROLLBACK TO SAVEPOINT `sp1`",
+			"release":    "RELEASE SAVEPOINT `sp1`",
+		},
+		map[string]interface{}{
+			"dialect":    SQLServer,
+			"savepoint":  "SAVE TRANSACTION [sp1]",
+			"rollbackTo": "ROLLBACK TRANSACTION [sp1]",
+			"release":    "",
+		},
+		map[string]interface{}{
+			"dialect":    Oracle,
+			"savepoint":  `SAVEPOINT "sp1"`,
+			"rollbackTo": `ROLLBACK TO SAVEPOINT "sp1"`,
+			"release":    "",
+		},
+	}
+
+	for _, c := range cases {
+		db, mock, _ := sqlmock.New()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(c["savepoint"].(string))).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(c["rollbackTo"].(string))).WillReturnResult(sqlmock.NewResult(0, 0))
+		if c["release"] != "" {
+			mock.ExpectExec(regexp.QuoteMeta(c["release"].(string))).WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectCommit()
+
+		tx, err := New(db, WithDialect(c["dialect"].(Dialect))).BeginTx()
+		assert.NoError(t, err)
+
+		assert.NoError(t, tx.Savepoint("sp1"))
+		assert.NoError(t, tx.RollbackTo("sp1"))
+		assert.NoError(t, tx.Release("sp1"))
+		assert.NoError(t, tx.Commit())
+
+		assert.NoError(t, mock.ExpectationsWereMet(), c["dialect"].(Dialect).Name())
+
+		db.Close()
+	}
+}
+
+func TestTransactionWithTx(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO Foo").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`RELEASE SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO Bar").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`RELEASE SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := New(db, WithDialect(Postgres)).BeginTx()
+	assert.NoError(t, err)
+
+	err = tx.WithTx(func(tx Transaction) error {
+		_, err := tx.Exec(NewStatement("INSERT INTO Foo VALUES (1)"))
+		return err
+	})
+	assert.NoError(t, err)
+
+	fail := errors.New("fail")
+
+	err = tx.WithTx(func(tx Transaction) error {
+		tx.Exec(NewStatement("INSERT INTO Bar VALUES (1)"))
+		return fail
+	})
+	assert.Equal(t, fail, err)
+
+	// the outer transaction survives the failed inner one
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionWithTxRollbackFails(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+	broken := errors.New("broken")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "gdo_sp_\d+"`).WillReturnError(broken)
+
+	tx, err := New(db, WithDialect(Postgres)).BeginTx()
+	assert.NoError(t, err)
+
+	err = tx.WithTx(func(tx Transaction) error {
+		return fail
+	})
+
+	// the error of fn is kept along with that of the rollback
+	assert.True(t, errors.Is(err, fail))
+	assert.True(t, errors.Is(err, broken))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSavepointIntercepted(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	type ctxKey struct{}
+
+	var queries []string
+	var values []interface{}
+
+	g := New(db, WithDialect(Postgres), WithInterceptors(func(ctx context.Context, e *Execution, next func(ctx context.Context) error) error {
+		queries = append(queries, e.Statement.Query())
+		values = append(values, ctx.Value(ctxKey{}))
+
+		return next(ctx)
+	}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`ROLLBACK TO SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`RELEASE SAVEPOINT "gdo_sp_\d+"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := g.BeginTxContext(context.WithValue(context.Background(), ctxKey{}, "tx"), nil)
+	assert.NoError(t, err)
+
+	assert.Panics(t, func() {
+		tx.WithTx(func(tx Transaction) error {
+			panic("boom")
+		})
+	})
+
+	// the panicking inner transaction is rolled back and released
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Len(t, queries, 3)
+	assert.Regexp(t, `^RELEASE SAVEPOINT`, queries[2])
+	assert.Equal(t, []interface{}{"tx", "tx", "tx"}, values)
+}
@@ -0,0 +1,139 @@
+package gdo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SlowQuery describes a statement that took longer than the threshold set
+// with WithSlowQueries
+type SlowQuery struct {
+	Time time.Time `json:"time"`
+	Op   Operation `json:"op"`
+	// Query is the statement with its arguments interpolated and sensitive
+	// values redacted
+	Query    string        `json:"query"`
+	Params   []string      `json:"params,omitempty"`
+	Duration time.Duration `json:"duration"`
+	// Caller is the file:line of the code that ran the statement
+	Caller string `json:"caller"`
+	// TxID is the ID of the Transaction the statement ran in, or 0
+	TxID  uint64 `json:"tx_id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// WithSlowQueries reports every statement taking threshold or longer to
+// report, which is called on the goroutine that ran the statement.  For a
+// query the time is that taken to return the first rows, not to read them
+// all.  SlowQueryLog.Record can be used as report.
+func WithSlowQueries(threshold time.Duration, report func(SlowQuery)) Option {
+	return WithInterceptors(func(ctx context.Context, e *Execution, next func(ctx context.Context) error) error {
+		err := next(ctx)
+
+		if e.Duration < threshold {
+			return err
+		}
+
+		q := SlowQuery{
+			Time:     time.Now().Add(-e.Duration),
+			Op:       e.Op,
+			Query:    e.LastExecutedQuery(),
+			Params:   paramNames(e.Statement),
+			Duration: e.Duration,
+			Caller:   callSite(),
+			TxID:     e.TxID,
+		}
+
+		if err != nil {
+			q.Error = err.Error()
+		}
+
+		report(q)
+
+		return err
+	})
+}
+
+// pkgPrefix is the prefix of the names of the functions of this package
+var pkgPrefix = strings.TrimSuffix(runtime.FuncForPC(reflect.ValueOf(New).Pointer()).Name(), "New")
+
+// callSite returns the file:line of the first caller outside this package
+func callSite() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		f, more := frames.Next()
+
+		if !strings.HasPrefix(f.Function, pkgPrefix) {
+			return f.File + ":" + strconv.Itoa(f.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// SlowQueryLog keeps the most recent slow queries in a ring buffer.  It
+// serves them as JSON over HTTP, newest first, for a debug endpoint.
+type SlowQueryLog struct {
+	mu      sync.Mutex
+	entries []SlowQuery
+	next    int
+	full    bool
+}
+
+// NewSlowQueryLog returns a SlowQueryLog holding up to size entries
+func NewSlowQueryLog(size int) *SlowQueryLog {
+	if size < 1 {
+		size = 1
+	}
+
+	return &SlowQueryLog{entries: make([]SlowQuery, size)}
+}
+
+// Record adds q, replacing the oldest entry when the log is full
+func (l *SlowQueryLog) Record(q SlowQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = q
+	l.next = (l.next + 1) % len(l.entries)
+
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Entries returns the logged slow queries, newest first
+func (l *SlowQueryLog) Entries() []SlowQuery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.next
+	if l.full {
+		n = len(l.entries)
+	}
+
+	out := make([]SlowQuery, 0, n)
+
+	for i := 1; i <= n; i++ {
+		out = append(out, l.entries[(l.next-i+len(l.entries))%len(l.entries)])
+	}
+
+	return out
+}
+
+func (l *SlowQueryLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(l.Entries())
+}
@@ -0,0 +1,37 @@
+package gdo_test
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Mehokm/gdo"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+// TestSlowQueryCaller runs outside the package, as the caller of a slow query
+// is the first frame that is not in it
+func TestSlowQueryCaller(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE Foo").WillDelayFor(20 * time.Millisecond).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var got []gdo.SlowQuery
+
+	g := gdo.New(db, gdo.WithSlowQueries(10*time.Millisecond, func(q gdo.SlowQuery) {
+		got = append(got, q)
+	}))
+
+	_, file, line, _ := runtime.Caller(0)
+	_, err := g.Exec(gdo.NewStatement("UPDATE Foo SET a = 1"))
+	assert.NoError(t, err)
+
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, file+":"+strconv.Itoa(line+1), got[0].Caller)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
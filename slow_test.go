@@ -0,0 +1,80 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestSlowQueries(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE Foo").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM Foo WHERE id = ?")).
+		WithArgs(7).
+		WillDelayFor(20 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	var got []SlowQuery
+
+	g := New(db, WithSlowQueries(10*time.Millisecond, func(q SlowQuery) {
+		got = append(got, q)
+	}))
+
+	_, err := g.Exec(NewStatement("UPDATE Foo SET a = 1"))
+	assert.NoError(t, err)
+
+	tx, err := g.BeginTx()
+	assert.NoError(t, err)
+	assert.NotZero(t, tx.ID())
+
+	s := NewStatement("SELECT * FROM Foo WHERE id = :id:")
+	s.BindNamedArg(sql.Named("id", 7))
+
+	_, err = tx.QueryContext(context.Background(), s)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	if assert.Len(t, got, 1) {
+		q := got[0]
+
+		assert.Equal(t, OpQuery, q.Op)
+		assert.Equal(t, "SELECT * FROM Foo WHERE id = 7", q.Query)
+		assert.Equal(t, []string{"id"}, q.Params)
+		assert.True(t, q.Duration >= 20*time.Millisecond)
+		assert.Equal(t, tx.ID(), q.TxID)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSlowQueryLog(t *testing.T) {
+	l := NewSlowQueryLog(2)
+
+	assert.Empty(t, l.Entries())
+
+	for _, q := range []string{"a", "b", "c"} {
+		l.Record(SlowQuery{Query: q})
+	}
+
+	assert.Equal(t, []SlowQuery{{Query: "c"}, {Query: "b"}}, l.Entries())
+
+	w := httptest.NewRecorder()
+	l.ServeHTTP(w, httptest.NewRequest("GET", "/debug/slow", nil))
+
+	var served []SlowQuery
+
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &served))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, l.Entries(), served)
+}
@@ -3,10 +3,7 @@ package gdo
 import (
 	"database/sql"
 	"errors"
-	"index/suffixarray"
-	"sort"
-	"strconv"
-	"strings"
+	"sync/atomic"
 )
 
 var ErrParameterMismatch = errors.New("gdo: you have a parameter mismatch")
@@ -16,6 +13,15 @@ type Statement struct {
 	namedArgs       []sql.NamedArg
 	args            []interface{}
 	isParameterized bool
+	// tmpl is the compiled form of the query as it was written.  Statements
+	// produced by executing keep the template of the statement they came from.
+	tmpl *template
+	// dialectTmpls holds the query compiled for each syntax other than that
+	// of tmpl it has been executed in
+	dialectTmpls atomic.Pointer[map[syntax]*template]
+	// binds holds the values bound by BindStruct and BindMap, to be matched
+	// again against the names of the query as read in another syntax
+	binds []binding
 }
 
 // NewStatement returns a Statement
@@ -23,18 +29,37 @@ func NewStatement(query string) *Statement {
 	var namedArgs []sql.NamedArg
 	var args []interface{}
 
-	isParameterized := checkIsParameterized(query)
+	tmpl := compile(query)
 
 	return &Statement{
 		query:           query,
 		namedArgs:       namedArgs,
 		args:            args,
-		isParameterized: isParameterized,
+		isParameterized: tmpl.isParameterized(),
+		tmpl:            tmpl,
 	}
 }
 
+// Query returns the SQL of the statement.  For a statement that has been
+// executed this is the query with its named parameters rewritten.
+func (stmt *Statement) Query() string {
+	return stmt.query
+}
+
+// NamedArgs returns the named arguments bound to the statement
+func (stmt *Statement) NamedArgs() []sql.NamedArg {
+	return stmt.namedArgs
+}
+
+// Args returns the positional arguments of the statement, which for an
+// executed statement are the values of its placeholders in order
+func (stmt *Statement) Args() []interface{} {
+	return stmt.args
+}
+
 func (stmt *Statement) BindNamedArgs(namedArgs []sql.NamedArg) {
 	stmt.namedArgs = namedArgs
+	stmt.binds = nil
 }
 
 func (stmt *Statement) BindNamedArg(namedArg sql.NamedArg) {
@@ -49,98 +74,157 @@ func (stmt *Statement) BindArg(arg interface{}) {
 	stmt.args = append(stmt.args, arg)
 }
 
-func (stmt *Statement) lastExecutedQuery() string {
-	lastExecQuery := stmt.query
+// template returns the compiled query, compiling it for a Statement that was
+// not built by NewStatement
+func (stmt *Statement) template() *template {
+	if stmt.tmpl == nil {
+		return compile(stmt.query)
+	}
 
-	if len(stmt.args) > 0 {
-		index := suffixarray.New([]byte(stmt.query))
-		inds := index.Lookup([]byte("?"), -1)
+	return stmt.tmpl
+}
 
-		sort.Ints(inds)
+// templateFor returns the query compiled for d.  NewStatement compiles the
+// query as MySQL, so for a dialect with another syntax it is compiled again,
+// once per Statement.
+func (stmt *Statement) templateFor(d Dialect) *template {
+	syn := syntaxOf(d)
 
-		interQuery := stmt.query
+	if stmt.tmpl == nil {
+		return compileSyntax(stmt.query, syn)
+	}
 
-		var padding int
-		for i, ind := range inds {
-			var s string
+	if stmt.tmpl.syntax == syn {
+		return stmt.tmpl
+	}
 
-			arg := stmt.args[i]
+	for {
+		old := stmt.dialectTmpls.Load()
 
-			switch arg.(type) {
-			case int:
-				s = strconv.Itoa(arg.(int))
-			case int64:
-				s = strconv.Itoa(int(arg.(int64)))
-			case float32:
-				s = strconv.FormatFloat(float64(arg.(float32)), 'f', -1, 32)
-			case float64:
-				s = strconv.FormatFloat(arg.(float64), 'f', -1, 64)
-			case string:
-				s = "'" + arg.(string) + "'"
-			case nil:
-				s = "NULL"
+		if old != nil {
+			if t, ok := (*old)[syn]; ok {
+				return t
 			}
+		}
 
-			interQuery = insertAt(interQuery, s, ind+padding-i)
+		m := make(map[syntax]*template)
 
-			padding += len(s)
+		if old != nil {
+			for k, v := range *old {
+				m[k] = v
+			}
 		}
 
-		lastExecQuery = interQuery
-	}
+		t := compileSyntax(stmt.query, syn)
+		m[syn] = t
 
-	return lastExecQuery
+		if stmt.dialectTmpls.CompareAndSwap(old, &m) {
+			return t
+		}
+	}
 }
 
-func processStatment(s *Statement) (*Statement, error) {
-	index := suffixarray.New([]byte(s.query))
+// namedArgsFor returns the named args of stmt with the values bound by
+// BindStruct and BindMap matched against the names of tmpl rather than those
+// of the query as written
+func (stmt *Statement) namedArgsFor(tmpl *template) ([]sql.NamedArg, error) {
+	if len(stmt.binds) == 0 || tmpl == stmt.template() {
+		return stmt.namedArgs, nil
+	}
 
-	indexMap := make(map[int]sql.NamedArg)
+	var namedArgs []sql.NamedArg
+	var next int
 
-	var indicies []int
-	var args []interface{}
-	var toReplace []string
+	for _, b := range stmt.binds {
+		matched, err := matchValues(tmpl.names, b.values, b.opts)
 
-	var argCount int
-	for _, arg := range s.namedArgs {
-		argName := delim + arg.Name + delim
+		if err != nil {
+			return nil, err
+		}
 
-		inds := index.Lookup([]byte(argName), -1)
+		namedArgs = append(namedArgs, stmt.namedArgs[next:b.at]...)
+		namedArgs = append(namedArgs, matched...)
+		next = b.at + b.n
+	}
 
-		if len(inds) <= 0 {
-			break
-		}
+	return append(namedArgs, stmt.namedArgs[next:]...), nil
+}
+
+// hasNamedArgs reports whether any named args or values are bound to stmt
+func (stmt *Statement) hasNamedArgs() bool {
+	return len(stmt.namedArgs) > 0 || len(stmt.binds) > 0
+}
 
-		for _, ind := range inds {
-			indexMap[ind] = arg
+func (stmt *Statement) lastExecutedQuery(cfg *config) string {
+	d := cfg.getDialect()
 
-			indicies = append(indicies, ind)
+	if len(stmt.namedArgs) > 0 && stmt.tmpl != nil && stmt.tmpl.isParameterized() {
+		q, err := stmt.tmpl.render(cfg, cfg.redactNamedArgs(stmt.namedArgs), func(buf []byte, v interface{}) []byte {
+			return append(buf, formatArg(d, v)...)
+		})
+
+		if err == nil {
+			return q
 		}
+	}
 
-		toReplace = append(toReplace, argName, "?")
+	return Interpolate(d, stmt.query, stmt.redactedArgs(cfg))
+}
 
-		argCount++
+// redactedArgs returns the positional args of stmt with the values bound to
+// redacted names wrapped in Sensitive.  When the args of a statement executed
+// from named ones cannot be matched to the names of its query, all of them
+// are wrapped.
+func (stmt *Statement) redactedArgs(cfg *config) []interface{} {
+	if len(stmt.namedArgs) == 0 {
+		return stmt.args
 	}
 
-	if argCount != len(s.namedArgs) {
-		return nil, ErrParameterMismatch
+	var params []string
+
+	if stmt.tmpl != nil {
+		params = stmt.tmpl.params
 	}
 
-	sort.Ints(indicies)
+	args := make([]interface{}, len(stmt.args))
+
+	for i, arg := range stmt.args {
+		if len(params) != len(stmt.args) || cfg.isRedacted(params[i]) {
+			arg = Sensitive(arg)
+		}
 
-	for _, ind := range indicies {
-		args = append(args, indexMap[ind].Value)
+		args[i] = arg
 	}
 
-	replacedSQL := strings.NewReplacer(toReplace...).Replace(s.query)
+	return args
+}
+
+func processStatment(cfg *config, s *Statement) (*Statement, error) {
+	d := cfg.getDialect()
+	tmpl := s.templateFor(d)
+
+	namedArgs, err := s.namedArgsFor(tmpl)
+
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, len(tmpl.params))
+
+	query, err := tmpl.render(cfg, namedArgs, func(buf []byte, v interface{}) []byte {
+		args = append(args, v)
+
+		return append(buf, d.Placeholder(len(args))...)
+	})
+
+	if err != nil {
+		return nil, err
+	}
 
 	return &Statement{
-		query:     replacedSQL,
-		namedArgs: s.namedArgs,
+		query:     query,
+		namedArgs: namedArgs,
 		args:      args,
+		tmpl:      tmpl,
 	}, nil
 }
-
-func insertAt(str, toIns string, pos int) string {
-	return str[:pos] + toIns + str[pos+1:]
-}
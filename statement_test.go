@@ -29,6 +29,7 @@ func TestNewStatement(t *testing.T) {
 			namedArgs:       namesArgs,
 			args:            args,
 			isParameterized: c["isParameterized"].(bool),
+			tmpl:            compile(c["query"].(string)),
 		}
 
 		assert.Equal(t, expected, NewStatement(c["query"].(string)))
@@ -79,17 +80,19 @@ func TestProcessStatement(t *testing.T) {
 	}
 
 	for _, c := range cases {
+		stmt := NewStatement(c["query"].(string))
+
 		expected := &Statement{
 			query:     c["expectedQuery"].(string),
 			args:      c["args"].([]interface{}),
 			namedArgs: []sql.NamedArg{sql.Named("a", a), sql.Named("b", b)},
+			tmpl:      stmt.tmpl,
 		}
 
-		stmt := NewStatement(c["query"].(string))
 		stmt.BindNamedArg(sql.Named("a", a))
 		stmt.BindNamedArg(sql.Named("b", b))
 
-		newStmt, err := processStatment(stmt)
+		newStmt, err := processStatment(newConfig(nil), stmt)
 
 		if err != nil {
 			assert.Equal(t, c["error"], err)
@@ -102,8 +105,8 @@ func TestProcessStatement(t *testing.T) {
 }
 
 func TestLastExecutedQuery(t *testing.T) {
-	a := string(rand.Intn(255))
-	b := string(rand.Intn(255))
+	a := string(rune('a' + rand.Intn(26)))
+	b := string(rune('a' + rand.Intn(26)))
 
 	cases := []map[string]interface{}{
 		map[string]interface{}{
@@ -129,9 +132,68 @@ func TestLastExecutedQuery(t *testing.T) {
 		stmt.BindNamedArg(sql.Named("a", a))
 		stmt.BindNamedArg(sql.Named("b", b))
 
-		newStmt, err := processStatment(stmt)
+		newStmt, err := processStatment(newConfig(nil), stmt)
 
-		assert.Equal(t, c["expectedQuery"].(string), newStmt.lastExecutedQuery())
+		assert.Equal(t, c["expectedQuery"].(string), newStmt.lastExecutedQuery(newConfig(nil)))
 		assert.NoError(t, err)
 	}
 }
+
+func BenchmarkNewStatement(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		NewStatement("SELECT * FROM Foo WHERE id = :id: AND bar = :bar: AND baz IN (:baz:)")
+	}
+}
+
+func BenchmarkProcessStatement(b *testing.B) {
+	cfg := newConfig(nil)
+
+	stmt := NewStatement("SELECT * FROM Foo WHERE id = :id: AND bar = :bar: AND baz = :id:")
+	stmt.BindNamedArg(sql.Named("id", 1))
+	stmt.BindNamedArg(sql.Named("bar", "bar"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := processStatment(cfg, stmt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkProcessStatementSlice(b *testing.B) {
+	cfg := newConfig([]Option{WithDialect(Postgres)})
+
+	stmt := NewStatement("SELECT * FROM Foo WHERE id IN (:ids:) AND bar = :bar:")
+	stmt.BindNamedArg(sql.Named("ids", []int{1, 2, 3, 4, 5}))
+	stmt.BindNamedArg(sql.Named("bar", "bar"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := processStatment(cfg, stmt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLastExecutedQuery(b *testing.B) {
+	cfg := newConfig(nil)
+
+	stmt := NewStatement("SELECT * FROM Foo WHERE id = :id: AND bar = :bar: AND baz = :id:")
+	stmt.BindNamedArg(sql.Named("id", 1))
+	stmt.BindNamedArg(sql.Named("bar", "bar"))
+
+	newStmt, _ := processStatment(cfg, stmt)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		newStmt.lastExecutedQuery(cfg)
+	}
+}
@@ -0,0 +1,203 @@
+package gdo
+
+import (
+	"database/sql"
+	"sync"
+)
+
+// maxTemplates bounds the cache of compiled queries
+const maxTemplates = 1024
+
+// template is a query compiled once into the SQL between its named parameters
+// and the names of those parameters, so that executing or interpolating a
+// statement never has to scan the query again
+type template struct {
+	// segments has one more entry than params: segments[i] comes before
+	// params[i] and the last segment ends the query
+	segments []string
+	params   []string
+	// index holds the positions in params of each name
+	index map[string][]int
+	// names holds each name once, in order of first use
+	names []string
+	size  int
+	// syntax is the syntax the query was read in
+	syntax syntax
+}
+
+// binder appends the SQL standing in for a single value to buf
+type binder func(buf []byte, v interface{}) []byte
+
+type templateKey struct {
+	query  string
+	syntax syntax
+}
+
+var templates = struct {
+	sync.RWMutex
+	m map[templateKey]*template
+}{m: make(map[templateKey]*template)}
+
+// compile returns the template for query written for MySQL, compiling it on
+// first use.  Queries are cached by their text, so statements built with
+// NewStatement for the same query share one template.
+func compile(query string) *template {
+	return compileSyntax(query, mysqlSyntax)
+}
+
+// compileFor is compile for a query written for d
+func compileFor(query string, d Dialect) *template {
+	return compileSyntax(query, syntaxOf(d))
+}
+
+func compileSyntax(query string, syn syntax) *template {
+	key := templateKey{query, syn}
+
+	templates.RLock()
+	t, ok := templates.m[key]
+	templates.RUnlock()
+
+	if ok {
+		return t
+	}
+
+	t = newTemplate(query, syn)
+
+	templates.Lock()
+	if len(templates.m) >= maxTemplates {
+		templates.m = make(map[templateKey]*template)
+	}
+	templates.m[key] = t
+	templates.Unlock()
+
+	return t
+}
+
+func newTemplate(query string, syn syntax) *template {
+	t := &template{
+		index:  make(map[string][]int),
+		size:   len(query),
+		syntax: syn,
+	}
+
+	var segment []byte
+
+	for _, tok := range lexSyntax(query, syn) {
+		if tok.kind != tokenNamed {
+			segment = append(segment, tok.text...)
+			continue
+		}
+
+		if _, ok := t.index[tok.name]; !ok {
+			t.names = append(t.names, tok.name)
+		}
+
+		t.index[tok.name] = append(t.index[tok.name], len(t.params))
+
+		t.segments = append(t.segments, string(segment))
+		t.params = append(t.params, tok.name)
+
+		segment = segment[:0]
+	}
+
+	t.segments = append(t.segments, string(segment))
+
+	return t
+}
+
+func (t *template) isParameterized() bool {
+	return len(t.params) > 0
+}
+
+func (t *template) queryNamedArgs() queryNamedArgs {
+	return queryNamedArgs{dict: t.index, total: len(t.params)}
+}
+
+// placeholders returns the query with one placeholder for every occurrence of
+// a named parameter, which is the form a PreparedStatement is prepared in
+func (t *template) placeholders(d Dialect) string {
+	buf := make([]byte, 0, t.size+4*len(t.params))
+
+	for i, segment := range t.segments {
+		if i > 0 {
+			buf = append(buf, d.Placeholder(i)...)
+		}
+
+		buf = append(buf, segment...)
+	}
+
+	return string(buf)
+}
+
+// render writes the query with each named parameter replaced by what bind
+// produces for its value.  Slices are expanded to one value per element and
+// empty slices are handled according to the EmptySlice mode of cfg.
+func (t *template) render(cfg *config, namedArgs []sql.NamedArg, bind binder) (string, error) {
+	for _, arg := range namedArgs {
+		if _, ok := t.index[arg.Name]; !ok {
+			return "", ErrParameterMismatch
+		}
+	}
+
+	buf := make([]byte, 0, t.size+4*len(t.params))
+
+	next := t.segments[0]
+
+	for i, name := range t.params {
+		buf = append(buf, next...)
+		next = t.segments[i+1]
+
+		arg, ok := findNamedArg(namedArgs, name)
+
+		if !ok {
+			return "", ErrParameterMismatch
+		}
+
+		values, ok := expandable(arg.Value)
+
+		if !ok {
+			buf = bind(buf, arg.Value)
+			continue
+		}
+
+		if len(values) == 0 {
+			switch cfg.getEmptySlice() {
+			case EmptySliceNull:
+				buf = append(buf, "NULL"...)
+				continue
+			case EmptySliceFalse:
+				if start, predicate, rest, ok := rewriteEmptyIn(string(buf), next); ok {
+					buf = append(buf[:start], predicate...)
+					next = rest
+					continue
+				}
+			}
+
+			return "", ErrEmptySlice
+		}
+
+		for j, v := range values {
+			if j > 0 {
+				buf = append(buf, ", "...)
+			}
+
+			buf = bind(buf, v)
+		}
+	}
+
+	buf = append(buf, next...)
+
+	return string(buf), nil
+}
+
+// findNamedArg returns the last argument bound to name, so that binding a
+// name again replaces its value
+func findNamedArg(namedArgs []sql.NamedArg, name string) (sql.NamedArg, bool) {
+	for i := len(namedArgs) - 1; i >= 0; i-- {
+		if namedArgs[i].Name == name {
+			return namedArgs[i], true
+		}
+	}
+
+	return sql.NamedArg{}, false
+}
@@ -0,0 +1,32 @@
+package gdo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	query := "SELECT * FROM Foo WHERE id = :a: AND s = ':b:' AND bar = :b: AND baz = :a:"
+
+	tmpl := compile(query)
+
+	assert.Equal(t, []string{"SELECT * FROM Foo WHERE id = ", " AND s = ':b:' AND bar = ", " AND baz = ", ""}, tmpl.segments)
+	assert.Equal(t, []string{"a", "b", "a"}, tmpl.params)
+	assert.Equal(t, []string{"a", "b"}, tmpl.names)
+	assert.Equal(t, map[string][]int{"a": []int{0, 2}, "b": []int{1}}, tmpl.index)
+
+	// compiled once and shared
+	assert.True(t, tmpl == compile(query))
+	assert.True(t, tmpl == NewStatement(query).tmpl)
+
+	assert.Equal(t, "SELECT * FROM Foo WHERE id = $1 AND s = ':b:' AND bar = $2 AND baz = $3", tmpl.placeholders(Postgres))
+}
+
+func TestCompileWithoutParameters(t *testing.T) {
+	tmpl := compile("SELECT * FROM Foo WHERE id = ?")
+
+	assert.False(t, tmpl.isParameterized())
+	assert.Equal(t, []string{"SELECT * FROM Foo WHERE id = ?"}, tmpl.segments)
+	assert.Equal(t, "SELECT * FROM Foo WHERE id = ?", tmpl.placeholders(Postgres))
+}
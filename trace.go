@@ -0,0 +1,149 @@
+package gdo
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Tracer starts the spans gdo creates around statements and the begin,
+// commit and rollback of transactions
+type Tracer interface {
+	// StartSpan starts a span called name as a child of any span in ctx,
+	// returning a context carrying the new span
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// End ends the span, recording err when it is not nil
+	End(err error)
+}
+
+// Attribute is a key and value set on a Span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// WithTracer starts a span for each statement run through the GDO and for the
+// begin, commit and rollback of its transactions.  Statement spans are named
+// gdo.exec, gdo.query and gdo.prepare and carry the db.system, db.statement
+// (normalized) and db.rows_affected attributes.
+func WithTracer(t Tracer) Option {
+	return func(c *config) {
+		c.tracer = t
+		c.interceptors = append(c.interceptors, traceInterceptor)
+	}
+}
+
+func traceInterceptor(ctx context.Context, e *Execution, next func(ctx context.Context) error) error {
+	ctx, span := e.cfg.startSpan(ctx, "gdo."+string(e.Op))
+
+	span.SetAttributes(Attribute{"db.statement", normalize(e.Statement.Query(), syntaxOf(e.cfg.getDialect()))})
+
+	err := next(ctx)
+
+	if e.Op == OpExec && err == nil {
+		span.SetAttributes(Attribute{"db.rows_affected", e.RowsAffected})
+	}
+
+	span.End(err)
+
+	return err
+}
+
+// startSpan starts a span with the attributes common to all gdo spans, or a
+// span doing nothing when there is no Tracer
+func (c *config) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if c == nil || c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := c.tracer.StartSpan(ctx, name)
+
+	span.SetAttributes(Attribute{"db.system", c.getDialect().Name()})
+
+	if c.txID != 0 {
+		span.SetAttributes(Attribute{"gdo.tx_id", c.txID})
+	}
+
+	return ctx, span
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+
+func (noopSpan) End(err error) {}
+
+// WithSQLComments appends the tags returned by tags for the context of each
+// statement to its query as a sqlcommenter comment, e.g.
+// /*route='%2Fusers',traceparent='00-...'*/, so that database logs can be
+// correlated with traces.  Give it after WithTracer for the context to carry
+// the span of the statement.  The query of a statement run on a
+// PreparedStatement is that it was prepared with.
+func WithSQLComments(tags func(ctx context.Context) map[string]string) Option {
+	return WithInterceptors(func(ctx context.Context, e *Execution, next func(ctx context.Context) error) error {
+		if comment := sqlComment(tags(ctx)); comment != "" {
+			e.Statement = &Statement{
+				query:           appendComment(e.Statement.query, comment, syntaxOf(e.cfg.getDialect())),
+				namedArgs:       e.Statement.namedArgs,
+				args:            e.Statement.args,
+				isParameterized: e.Statement.isParameterized,
+				tmpl:            e.Statement.tmpl,
+			}
+		}
+
+		return next(ctx)
+	})
+}
+
+// sqlComment formats tags as a sqlcommenter comment, sorted by key
+func sqlComment(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(tags))
+
+	for k, v := range tags {
+		pairs = append(pairs, commentEscape(k)+"='"+commentEscape(v)+"'")
+	}
+
+	sort.Strings(pairs)
+
+	return "/*" + strings.Join(pairs, ",") + "*/"
+}
+
+// commentEscape URL-encodes s, which also escapes any quote or * in it
+func commentEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+// appendComment adds comment to the end of query, written in syn, before any
+// final semicolon.  When query ends in a line comment, comment goes on a new
+// line after it.
+func appendComment(query, comment string, syn syntax) string {
+	trimmed := strings.TrimRight(query, " \t\r\n;")
+
+	if endsInLineComment(trimmed, syn) {
+		trimmed = strings.TrimRight(query, " \t\r\n")
+
+		return trimmed + "\n" + comment + query[len(trimmed):]
+	}
+
+	return trimmed + " " + comment + query[len(trimmed):]
+}
+
+// endsInLineComment reports whether query, written in syn, ends inside a line
+// comment
+func endsInLineComment(query string, syn syntax) bool {
+	l := lexer{src: query, syntax: syn}
+
+	l.run()
+
+	return l.openComment
+}
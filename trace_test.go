@@ -0,0 +1,183 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type spanKey struct{}
+
+// recorder is an in-memory Tracer
+type recorder struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]interface{}
+	err    error
+	ended  bool
+}
+
+func (r *recorder) StartSpan(ctx context.Context, name string) (context.Context, Span) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	s := &recordedSpan{name: name, parent: parent, attrs: make(map[string]interface{})}
+	r.spans = append(r.spans, s)
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) End(err error) {
+	s.err = err
+	s.ended = true
+}
+
+func TestTracer(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE Foo SET a = $1 WHERE id IN ($2, $3)")).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT").WillReturnError(fail)
+	mock.ExpectRollback()
+
+	r := &recorder{}
+	g := New(db, WithDialect(Postgres), WithTracer(r))
+
+	root, _ := r.StartSpan(context.Background(), "root")
+
+	tx, err := g.BeginTxContext(root, nil)
+	assert.NoError(t, err)
+
+	s := NewStatement("UPDATE Foo SET a = :a: WHERE id IN (:ids:)")
+	s.BindNamedArgs([]sql.NamedArg{sql.Named("a", 1), sql.Named("ids", []int{1, 2})})
+
+	_, err = tx.ExecContext(root, s)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	err = g.WithTx(root, nil, func(tx Transaction) error {
+		_, err := tx.Query(NewStatement("SELECT 1"))
+		return err
+	})
+	assert.Equal(t, fail, err)
+
+	var names []string
+
+	for _, s := range r.spans {
+		names = append(names, s.name)
+
+		assert.True(t, s.ended || s.name == "root", s.name)
+
+		if s.name != "root" {
+			assert.Equal(t, "postgres", s.attrs["db.system"])
+		}
+	}
+
+	assert.Equal(t, []string{"root", "gdo.begin", "gdo.exec", "gdo.commit", "gdo.begin", "gdo.query", "gdo.rollback"}, names)
+
+	begin, exec, commit := r.spans[1], r.spans[2], r.spans[3]
+
+	assert.Equal(t, r.spans[0], begin.parent)
+	assert.Equal(t, r.spans[0], exec.parent)
+	assert.Equal(t, r.spans[0], commit.parent)
+	assert.Equal(t, tx.ID(), begin.attrs["gdo.tx_id"])
+	assert.Equal(t, tx.ID(), exec.attrs["gdo.tx_id"])
+	assert.Equal(t, "UPDATE Foo SET a = ? WHERE id IN (?)", exec.attrs["db.statement"])
+	assert.Equal(t, int64(2), exec.attrs["db.rows_affected"])
+
+	query := r.spans[5]
+
+	assert.Equal(t, fail, query.err)
+	assert.NotContains(t, query.attrs, "db.rows_affected")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLComments(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM Foo /*route='%2Fusers%2F%7Bid%7D',traceparent='00-abc-01'*/;")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT 1")).WillReturnRows(sqlmock.NewRows([]string{"1"}))
+
+	g := New(db, WithSQLComments(func(ctx context.Context) map[string]string {
+		route, _ := ctx.Value(spanKey{}).(string)
+
+		if route == "" {
+			return nil
+		}
+
+		return map[string]string{"traceparent": "00-abc-01", "route": route}
+	}))
+
+	r, err := g.ExecContext(context.WithValue(context.Background(), spanKey{}, "/users/{id}"), NewStatement("DELETE FROM Foo;"))
+	assert.NoError(t, err)
+	assert.Equal(t, "DELETE FROM Foo /*route='%2Fusers%2F%7Bid%7D',traceparent='00-abc-01'*/;", r.LastExecutedQuery())
+
+	_, err = g.Query(NewStatement("SELECT 1"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "/*k='it%27s%20a%20%2A%2Ftest'*/", sqlComment(map[string]string{"k": "it's a */test"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAppendComment(t *testing.T) {
+	cases := []map[string]string{
+		{
+			"query":    "SELECT 1",
+			"expected": "SELECT 1 /*c*/",
+		},
+		{
+			"query":    "SELECT 1;\n",
+			"expected": "SELECT 1 /*c*/;\n",
+		},
+		{
+			"query":    "SELECT 1 -- note",
+			"expected": "SELECT 1 -- note\n/*c*/",
+		},
+		{
+			"query":    "SELECT 1 -- note\n",
+			"expected": "SELECT 1 -- note\n/*c*/\n",
+		},
+		{
+			"query":    "SELECT 1 # note;\r\n",
+			"expected": "SELECT 1 # note;\n/*c*/\r\n",
+		},
+		{
+			"query":    "SELECT '-- not a comment'",
+			"expected": "SELECT '-- not a comment' /*c*/",
+		},
+		{
+			"query":    "SELECT 1 -- note\nFROM Foo",
+			"expected": "SELECT 1 -- note\nFROM Foo /*c*/",
+		},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c["expected"], appendComment(c["query"], "/*c*/", mysqlSyntax), c["query"])
+	}
+}
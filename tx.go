@@ -3,10 +3,77 @@ package gdo
 import (
 	"context"
 	"database/sql"
+	"sync/atomic"
 )
 
+// txSeq numbers the transactions begun through gdo
+var txSeq uint64
+
 type Transaction struct {
 	*sql.Tx
+	cfg *config
+	// ctx is the context the transaction was begun with, the parent of the
+	// spans of its commit and rollback
+	ctx context.Context
+}
+
+type txBeginner interface {
+	BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+}
+
+// beginTx begins a transaction on b, which carries a copy of cfg identifying
+// it
+func beginTx(ctx context.Context, b txBeginner, cfg *config, opts *sql.TxOptions) (Transaction, error) {
+	spanCtx, span := cfg.startSpan(ctx, "gdo.begin")
+
+	tx, err := b.BeginTx(spanCtx, opts)
+
+	if err != nil {
+		span.End(err)
+
+		return Transaction{cfg: cfg}, err
+	}
+
+	t := Transaction{Tx: tx, cfg: cfg.withTxID(atomic.AddUint64(&txSeq, 1)), ctx: ctx}
+
+	span.SetAttributes(Attribute{"gdo.tx_id", t.ID()})
+	span.End(nil)
+
+	return t, nil
+}
+
+// ID returns a number identifying the transaction within the process, which
+// is what Execution.TxID holds for the statements run in it
+func (tx Transaction) ID() uint64 {
+	return tx.cfg.getTxID()
+}
+
+// Commit commits the transaction
+func (tx Transaction) Commit() error {
+	_, span := tx.cfg.startSpan(tx.context(), "gdo.commit")
+
+	err := tx.Tx.Commit()
+	span.End(err)
+
+	return err
+}
+
+// Rollback aborts the transaction
+func (tx Transaction) Rollback() error {
+	_, span := tx.cfg.startSpan(tx.context(), "gdo.rollback")
+
+	err := tx.Tx.Rollback()
+	span.End(err)
+
+	return err
+}
+
+func (tx Transaction) context() context.Context {
+	if tx.ctx == nil {
+		return context.Background()
+	}
+
+	return tx.ctx
 }
 
 func (tx Transaction) Exec(s *Statement) (ExecResult, error) {
@@ -14,7 +81,7 @@ func (tx Transaction) Exec(s *Statement) (ExecResult, error) {
 }
 
 func (tx Transaction) ExecContext(ctx context.Context, s *Statement) (ExecResult, error) {
-	return doExecCtx(tx.Tx.ExecContext, ctx, s)
+	return doExecCtx(tx.Tx.ExecContext, ctx, tx.cfg, s)
 }
 
 func (tx Transaction) Query(s *Statement) (QueryResult, error) {
@@ -22,7 +89,7 @@ func (tx Transaction) Query(s *Statement) (QueryResult, error) {
 }
 
 func (tx Transaction) QueryContext(ctx context.Context, s *Statement) (QueryResult, error) {
-	return doQueryCtx(tx.Tx.QueryContext, ctx, s)
+	return doQueryCtx(tx.Tx.QueryContext, ctx, tx.cfg, s)
 }
 
 func (tx Transaction) QueryRow(s *Statement) QueryRowResult {
@@ -30,5 +97,37 @@ func (tx Transaction) QueryRow(s *Statement) QueryRowResult {
 }
 
 func (tx Transaction) QueryRowContext(ctx context.Context, s *Statement) QueryRowResult {
-	return doQueryRowCtx(tx.Tx.QueryContext, ctx, s)
+	return doQueryRowCtx(tx.Tx.QueryContext, ctx, tx.cfg, s)
+}
+
+func (tx Transaction) Prepare(query string) (*PreparedStatement, error) {
+	return tx.PrepareContext(context.Background(), query)
+}
+
+func (tx Transaction) PrepareContext(ctx context.Context, query string) (*PreparedStatement, error) {
+	return prepareContext(ctx, tx.Tx, tx.cfg, query)
+}
+
+// Stmt returns a transaction-specific copy of a statement prepared outside the
+// transaction, like sql.Tx.Stmt.  The copy keeps the named parameters of ps
+// and the arguments bound to it so far.
+func (tx Transaction) Stmt(ps *PreparedStatement) *PreparedStatement {
+	return tx.StmtContext(context.Background(), ps)
+}
+
+func (tx Transaction) StmtContext(ctx context.Context, ps *PreparedStatement) *PreparedStatement {
+	return &PreparedStatement{
+		Stmt: tx.Tx.StmtContext(ctx, ps.Stmt),
+		Statement: &Statement{
+			query:           ps.query,
+			namedArgs:       append([]sql.NamedArg(nil), ps.namedArgs...),
+			args:            append([]interface{}(nil), ps.args...),
+			isParameterized: ps.isParameterized,
+			tmpl:            ps.tmpl,
+		},
+		queryNamedArgs: ps.queryNamedArgs,
+		cfg:            tx.cfg,
+		preparer:       tx.Tx,
+		arities:        &stmtCache{},
+	}
 }
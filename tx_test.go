@@ -0,0 +1,250 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+func TestTransactionPrepare(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	p := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO Foo (a, b) VALUES (?, ?)"))
+	p.ExpectExec().WithArgs(1, "x").WillReturnResult(sqlmock.NewResult(1, 1))
+	p.ExpectExec().WithArgs(2, "y").WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	tx, err := New(db).BeginTx()
+	assert.NoError(t, err)
+
+	ps, err := tx.Prepare("INSERT INTO Foo (a, b) VALUES (:a:, :b:)")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"a": []int{0}, "b": []int{1}}, ps.queryNamedArgs.dict)
+
+	for i, b := range []string{"x", "y"} {
+		ps.BindNamedArgs([]sql.NamedArg{sql.Named("a", i+1), sql.Named("b", b)})
+
+		r, err := ps.Exec()
+		assert.NoError(t, err)
+
+		id, _ := r.LastInsertId()
+		assert.Equal(t, int64(i+1), id)
+	}
+
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionStmt(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	p := mock.ExpectPrepare(regexp.QuoteMeta("INSERT INTO Foo (a, b) VALUES (?, ?)"))
+	mock.ExpectBegin()
+	p.ExpectExec().WithArgs(1, "x").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	g := New(db)
+
+	ps, err := g.Prepare("INSERT INTO Foo (a, b) VALUES (:a:, :b:)")
+	assert.NoError(t, err)
+
+	ps.BindNamedArg(sql.Named("a", 1))
+
+	tx, err := g.BeginTx()
+	assert.NoError(t, err)
+
+	txps := tx.Stmt(ps)
+	txps.BindNamedArg(sql.Named("b", "x"))
+
+	_, err = txps.Exec()
+	assert.NoError(t, err)
+
+	assert.Equal(t, ps.queryNamedArgs, txps.queryNamedArgs)
+	assert.Equal(t, []sql.NamedArg{sql.Named("a", 1)}, ps.namedArgs)
+
+	assert.NoError(t, tx.Rollback())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "sql state " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+func TestWithTx(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Foo").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	g := New(db)
+
+	err := g.WithTx(context.Background(), nil, func(tx Transaction) error {
+		_, err := tx.Exec(NewStatement("UPDATE Foo SET a = 1"))
+		return err
+	})
+	assert.NoError(t, err)
+
+	fail := errors.New("fail")
+
+	err = g.WithTx(context.Background(), nil, func(tx Transaction) error {
+		return fail
+	})
+	assert.Equal(t, fail, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxPanic(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		New(db).WithTx(context.Background(), nil, func(tx Transaction) error {
+			panic("boom")
+		})
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxRetry(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE Foo").WillReturnError(sqlStateError("40001"))
+		mock.ExpectRollback()
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Foo").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var attempts int
+
+	g := New(db, WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+
+	err := g.WithTx(context.Background(), nil, func(tx Transaction) error {
+		attempts++
+
+		_, err := tx.Exec(NewStatement("UPDATE Foo SET a = 1"))
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTxRetryGivesUp(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	var attempts int
+
+	g := New(db, WithRetryPolicy(RetryPolicy{
+		MaxAttempts: 2,
+		Backoff:     ExponentialBackoff(time.Millisecond, time.Millisecond),
+	}))
+
+	err := g.WithTx(context.Background(), nil, func(tx Transaction) error {
+		attempts++
+		return sqlStateError("40P01")
+	})
+
+	assert.Equal(t, sqlStateError("40P01"), err)
+	assert.Equal(t, 2, attempts)
+
+	// errors that are not retryable are returned after the first attempt
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = g.WithTx(context.Background(), nil, func(tx Transaction) error {
+		attempts++
+		return sqlStateError("23505")
+	})
+
+	assert.Equal(t, sqlStateError("23505"), err)
+	assert.Equal(t, 3, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestContextWithTx(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE Foo").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT a FROM Foo").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(int64(1)))
+	mock.ExpectQuery("SELECT b FROM Foo").WillReturnRows(sqlmock.NewRows([]string{"b"}).AddRow(int64(2)))
+	mock.ExpectPrepare("DELETE FROM Foo").ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	g := New(db)
+
+	_, ok := TxFromContext(context.Background())
+	assert.False(t, ok)
+
+	tx, err := g.BeginTx()
+	assert.NoError(t, err)
+
+	// with a single connection held by tx, any of these going to the pool
+	// would block
+	ctx := ContextWithTx(context.Background(), tx)
+
+	s := NewStatement("UPDATE Foo SET a = :a:")
+	s.BindNamedArg(sql.Named("a", 1))
+
+	_, err = g.ExecContext(ctx, s)
+	assert.NoError(t, err)
+
+	r, err := g.QueryContext(ctx, NewStatement("SELECT a FROM Foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, Rows{Row{"a": int64(1)}}, r.FetchRows())
+
+	row := g.QueryRowContext(ctx, NewStatement("SELECT b FROM Foo"))
+	assert.NoError(t, row.LastError())
+	assert.Equal(t, Row{"b": int64(2)}, row.FetchRow())
+
+	ps, err := g.PrepareContext(ctx, "DELETE FROM Foo")
+	assert.NoError(t, err)
+
+	_, err = ps.ExecContext(ctx)
+	assert.NoError(t, err)
+
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
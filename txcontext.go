@@ -0,0 +1,19 @@
+package gdo
+
+import "context"
+
+type txContextKey struct{}
+
+// ContextWithTx returns a copy of ctx carrying tx.  GDO.ExecContext,
+// QueryContext, QueryRowContext and PrepareContext called with the returned
+// context, or one derived from it, run on tx instead of the pool.
+func ContextWithTx(ctx context.Context, tx Transaction) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the Transaction carried by ctx, if any
+func TxFromContext(ctx context.Context) (Transaction, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(Transaction)
+
+	return tx, ok && tx.Tx != nil
+}
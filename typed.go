@@ -0,0 +1,142 @@
+package gdo
+
+import (
+	"database/sql"
+	"errors"
+	"iter"
+	"reflect"
+)
+
+var ErrColumnCount = errors.New("gdo: fetching a non-struct type needs exactly one column")
+
+// typedScanner scans rows into values of type T: into the fields of a struct
+// by column name, or otherwise the single column into T itself
+type typedScanner[T any] struct {
+	fields [][]int
+	scalar bool
+	sink   []interface{}
+}
+
+func newTypedScanner[T any](cfg *config, cols []string) (*typedScanner[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	if !isStructTarget(t) {
+		if len(cols) != 1 {
+			return nil, ErrColumnCount
+		}
+
+		return &typedScanner[T]{scalar: true}, nil
+	}
+
+	fields, err := cfg.getMapper().mappingFor(t).plan(cols, cfg.getStrictMapping())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &typedScanner[T]{fields: fields, sink: make([]interface{}, len(cols))}, nil
+}
+
+// isStructTarget reports whether t is scanned into field by field rather
+// than as a single value
+func isStructTarget(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !isScanLeaf(t)
+}
+
+func (s *typedScanner[T]) scan(rows *sql.Rows) (T, error) {
+	var v T
+
+	if s.scalar {
+		err := rows.Scan(&v)
+
+		return v, err
+	}
+
+	err := rows.Scan(scanDest(reflect.ValueOf(&v).Elem(), s.fields, s.sink)...)
+
+	return v, err
+}
+
+// FetchAll reads every row of qr into a T and closes it.  A struct T gets
+// each column in the field it maps to, as FetchRowsTyped does.  Any other T is scanned
+// from a result of a single column.
+func FetchAll[T any](qr QueryResult) ([]T, error) {
+	var out []T
+
+	for v, err := range FetchStream[T](qr) {
+		if err != nil {
+			return out, err
+		}
+
+		out = append(out, v)
+	}
+
+	return out, nil
+}
+
+// FetchOne reads the first row of qrr into a T, as FetchAll does, returning
+// sql.ErrNoRows when there is none
+func FetchOne[T any](qrr QueryRowResult) (T, error) {
+	var zero T
+
+	if qrr.err != nil {
+		return zero, qrr.err
+	}
+
+	if qrr.Rows == nil {
+		return zero, sql.ErrNoRows
+	}
+
+	for v, err := range FetchStream[T](qrr.QueryResult) {
+		return v, err
+	}
+
+	return zero, sql.ErrNoRows
+}
+
+// FetchStream returns an iterator reading the rows of qr into a T one at a
+// time, as FetchAll does.  An error ends iteration, after being yielded with
+// the zero T.  The rows are closed when the loop ends, including by break.
+func FetchStream[T any](qr QueryResult) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		if qr.Rows == nil {
+			yield(zero, ErrNoCursor)
+			return
+		}
+
+		defer qr.Rows.Close()
+
+		s, err := newTypedScanner[T](qr.cfg, qr.Cols)
+
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for qr.Rows.Next() {
+			if qr.cursor != nil {
+				if err := qr.cursor.ctx.Err(); err != nil {
+					yield(zero, err)
+					return
+				}
+			}
+
+			v, err := s.scan(qr.Rows)
+
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if !yield(v, nil) {
+				return
+			}
+		}
+
+		if err := qr.Rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
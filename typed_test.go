@@ -0,0 +1,132 @@
+package gdo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/DATA-DOG/go-sqlmock.v1"
+)
+
+type typedUser struct {
+	ID      int64
+	Name    string `gdo:"user_name"`
+	Email   sql.NullString
+	private string
+}
+
+func TestFetchAll(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	// columns are matched by name, whatever their order, and extra columns
+	// are dropped
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"extra", "user_name", "id", "email"}).
+		AddRow("x", "a", int64(1), nil).
+		AddRow("y", "b", int64(2), "b@example.com"))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(3)).AddRow(int64(4)))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(3), "c"))
+
+	g := New(db)
+
+	r, err := g.Query(NewStatement("SELECT extra, user_name, id, email FROM Users"))
+	assert.NoError(t, err)
+
+	users, err := FetchAll[typedUser](r)
+	assert.NoError(t, err)
+	assert.Equal(t, []typedUser{
+		{ID: 1, Name: "a"},
+		{ID: 2, Name: "b", Email: sql.NullString{String: "b@example.com", Valid: true}},
+	}, users)
+
+	r, err = g.Query(NewStatement("SELECT id FROM Users"))
+	assert.NoError(t, err)
+
+	ids, err := FetchAll[int64](r)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, ids)
+
+	r, err = g.Query(NewStatement("SELECT id, name FROM Users"))
+	assert.NoError(t, err)
+
+	_, err = FetchAll[int64](r)
+	assert.Equal(t, ErrColumnCount, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchOne(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id", "user_name"}).
+		AddRow(int64(1), "a").
+		AddRow(int64(2), "b"))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT").WillReturnError(fail)
+
+	g := New(db)
+
+	u, err := FetchOne[typedUser](g.QueryRow(NewStatement("SELECT id, user_name FROM Users")))
+	assert.NoError(t, err)
+	assert.Equal(t, typedUser{ID: 1, Name: "a"}, u)
+
+	_, err = FetchOne[typedUser](g.QueryRow(NewStatement("SELECT id FROM Users")))
+	assert.Equal(t, sql.ErrNoRows, err)
+
+	_, err = FetchOne[typedUser](g.QueryRow(NewStatement("SELECT id FROM Users")))
+	assert.Equal(t, fail, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFetchStream(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fail := errors.New("fail")
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).
+		AddRow(int64(1)).
+		AddRow(int64(2)).
+		AddRow(int64(3)).
+		RowError(2, fail))
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)).AddRow(int64(2)))
+
+	g := New(db)
+
+	r, err := g.Query(NewStatement("SELECT id FROM Users"))
+	assert.NoError(t, err)
+
+	var ids []int64
+	var errs []error
+
+	for u, err := range FetchStream[typedUser](r) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		ids = append(ids, u.ID)
+	}
+
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.Equal(t, []error{fail}, errs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r, err = g.QueryContext(ctx, NewStatement("SELECT id FROM Users"))
+	assert.NoError(t, err)
+
+	cancel()
+
+	for _, err := range FetchStream[typedUser](r) {
+		assert.Equal(t, context.Canceled, err)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}